@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+// Cache is a thread safe map of K, V with a maximum capacity, evicting values by the cache's eviction policy when it is full.  The policy is
+// chosen by constructor:
+//   - NewFifoMapCache evicts the oldest values first
+//   - NewLruCache evicts the least recently used values first
+//   - NewLfuCache evicts the least frequently used values first
+//   - NewWTinyLfuCache admits values by their estimated frequency, evicting values unlikely to be used again
+type Cache[K comparable, V any] interface {
+	// Get returns the value of type V for the key of type K.  If the key is not found, the zero value of V is returned.
+	Get(key K) V
+	// Set sets the value of type V for the key of type K, evicting values if the cache is full
+	Set(key K, value V)
+	// Delete deletes the key of type K from the cache
+	Delete(key K)
+	// Contains returns true if the key of type K is in the cache
+	Contains(key K) bool
+	// Keys returns a slice of the keys in the cache
+	Keys() []K
+	// Values returns a slice of the values in the cache
+	Values() []V
+	// Len returns the number of values in the cache
+	Len() int
+	// Capacity returns the maximum number of values the cache holds
+	Capacity() int
+	// Resize changes the capacity of the cache, evicting values if the cache holds more than the capacity
+	Resize(capacity int)
+	// Clear removes all the values from the cache
+	Clear()
+}
+
+var (
+	_ Cache[int, int] = (*FifoMapCache[int, int])(nil)
+	_ Cache[int, int] = (*LruCache[int, int])(nil)
+	_ Cache[int, int] = (*LfuCache[int, int])(nil)
+	_ Cache[int, int] = (*WTinyLfuCache[int, int])(nil)
+)
@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCaches returns a cache of each eviction policy holding the capacity
+func testCaches(t *testing.T, capacity int) map[string]Cache[int, int] {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	return map[string]Cache[int, int]{
+		"fifo":     NewFifoMapCache[int, int](ctx, capacity),
+		"lru":      NewLruCache[int, int](capacity),
+		"lfu":      NewLfuCache[int, int](capacity),
+		"wTinyLfu": NewWTinyLfuCache[int, int](capacity),
+	}
+}
+
+func TestCache_SetAndGet_ReturnsValue(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// test
+			cache.Set(1, 1)
+			cache.Set(1, 2)
+
+			// assert
+			assert.Equal(t, 2, cache.Get(1), "Expected value to be 2")
+			assert.True(t, cache.Contains(1), "Expected key to be contained")
+			assert.Equal(t, 0, cache.Get(2), "Expected missing key to return zero value")
+			assert.False(t, cache.Contains(2), "Expected missing key not to be contained")
+			assert.Equal(t, 1, cache.Len(), "Expected length to be 1")
+		})
+	}
+}
+
+func TestCache_Delete_DeletesKey(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// setup
+			cache.Set(1, 1)
+			cache.Set(2, 2)
+
+			// test
+			cache.Delete(1)
+
+			// assert
+			assert.False(t, cache.Contains(1), "Expected deleted key not to be contained")
+			assert.Equal(t, []int{2}, cache.Keys(), "Expected keys to be [2]")
+			assert.Equal(t, []int{2}, cache.Values(), "Expected values to be [2]")
+		})
+	}
+}
+
+func TestCache_Clear_ClearsCache(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// setup
+			for i := 0; i < 10; i++ {
+				cache.Set(i, i)
+			}
+
+			// test
+			cache.Clear()
+
+			// assert
+			assert.Equal(t, 0, cache.Len(), "Expected cache to be empty")
+			assert.Empty(t, cache.Keys(), "Expected no keys")
+		})
+	}
+}
+
+func TestCache_Set_BeyondCapacity_EvictsValues(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// test
+			for i := 0; i < 1000; i++ {
+				cache.Set(i, i)
+				if f, ok := cache.(*FifoMapCache[int, int]); ok {
+					f.Sweep()
+				}
+			}
+
+			// assert
+			assert.LessOrEqual(t, cache.Len(), cache.Capacity(), "Expected cache to hold no more than its capacity")
+			assert.Greater(t, cache.Len(), 0, "Expected cache to hold values")
+			for _, key := range cache.Keys() {
+				assert.Equal(t, key, cache.Get(key), "Expected cached key to return its value")
+			}
+		})
+	}
+}
+
+func TestCache_Resize_ShrinksCache(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// setup
+			for i := 0; i < 100; i++ {
+				cache.Set(i, i)
+			}
+
+			// test
+			cache.Resize(25)
+
+			// assert
+			assert.Equal(t, 25, cache.Capacity(), "Expected capacity to be 25")
+			assert.LessOrEqual(t, cache.Len(), 25, "Expected cache to hold no more than its capacity")
+		})
+	}
+}
+
+func TestCache_ConcurrentAccess(t *testing.T) {
+	for name, cache := range testCaches(t, 100) {
+		t.Run(name, func(t *testing.T) {
+			// test
+			wg := &sync.WaitGroup{}
+			for g := 0; g < 8; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 1000; i++ {
+						key := i % 150
+						cache.Set(key, i)
+						cache.Get(key)
+						cache.Contains(key)
+						if i%10 == 0 {
+							cache.Delete(key)
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			// assert
+			assert.LessOrEqual(t, cache.Len(), cache.Capacity()*2, "Expected cache to stay bounded")
+		})
+	}
+}
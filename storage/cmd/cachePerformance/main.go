@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/rbell/toolchest/storage"
+	"math/rand"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -19,6 +20,9 @@ import (
 const (
 	iterations = 1000000
 	threads    = 10
+	// hitRatioCapacity is the capacity of the caches compared by hit ratio, hitRatioKeys the number of distinct keys they are read with
+	hitRatioCapacity = 1000
+	hitRatioKeys     = 100000
 )
 
 type (
@@ -26,15 +30,24 @@ type (
 		key int
 		val int
 	}
+	namedCache struct {
+		name  string
+		cache storage.Cache[string, int]
+	}
 )
 
 var (
 	cache     = make(map[int]int, 100)
 	syncCache sync.Map
 	mutex     sync.Mutex
-	mc        = storage.NewFifoMapCache[string, int](context.Background(), iterations)
-	safeMap   = storage.NewSafeMap[string, int](iterations)
-	ch        = make(chan data)
+	caches    = []namedCache{
+		{"FifoCache", storage.NewFifoMapCache[string, int](context.Background(), iterations)},
+		{"LruCache", storage.NewLruCache[string, int](iterations)},
+		{"LfuCache", storage.NewLfuCache[string, int](iterations)},
+		{"WTinyLfuCache", storage.NewWTinyLfuCache[string, int](iterations)},
+	}
+	safeMap = storage.NewSafeMap[string, int](iterations)
+	ch      = make(chan data)
 )
 
 func measure(name string, f func()) {
@@ -66,11 +79,13 @@ func main() {
 			mutex.Unlock()
 		})
 	})
-	measure("FifoCache", func() {
-		exec(func(i int) {
-			mc.Set(strconv.Itoa(i), 1)
+	for _, c := range caches {
+		measure(c.name, func() {
+			exec(func(i int) {
+				c.cache.Set(strconv.Itoa(i), 1)
+			})
 		})
-	})
+	}
 	measure("SafeMap", func() {
 		exec(func(i int) {
 			safeMap.Set(strconv.Itoa(i), 1)
@@ -92,4 +107,25 @@ func main() {
 			ch <- data{i, 1}
 		})
 	})
+	fmt.Println("Hit ratios reading", hitRatioKeys, "keys with a skewed distribution through caches of", hitRatioCapacity, "values")
+	hitRatio("FifoCache", storage.NewFifoMapCache[string, int](context.Background(), hitRatioCapacity))
+	hitRatio("LruCache", storage.NewLruCache[string, int](hitRatioCapacity))
+	hitRatio("LfuCache", storage.NewLfuCache[string, int](hitRatioCapacity))
+	hitRatio("WTinyLfuCache", storage.NewWTinyLfuCache[string, int](hitRatioCapacity))
+}
+
+// hitRatio reads keys following a zipf distribution through the cache, setting keys that miss, and prints the ratio of reads that hit
+func hitRatio(name string, c storage.Cache[string, int]) {
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.01, 1, hitRatioKeys-1)
+	hits := 0
+	for i := 0; i < iterations; i++ {
+		key := strconv.FormatUint(zipf.Uint64(), 10)
+		if c.Contains(key) {
+			c.Get(key)
+			hits++
+			continue
+		}
+		c.Set(key, i)
+	}
+	fmt.Printf("%s hit ratio: %.2f%%\n", name, float64(hits)*100/float64(iterations))
 }
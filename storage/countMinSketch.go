@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"hash/maphash"
+	"math/bits"
+)
+
+const (
+	// sketchDepth is the number of rows of counters estimating each key's frequency
+	sketchDepth = 4
+	// sketchMaxCount is the count a counter saturates at
+	sketchMaxCount = 15
+	// sketchCountersPerKey is the number of counters in each row for each key the sketch is sized for, reducing collisions between keys
+	sketchCountersPerKey = 4
+	// sketchSamplesPerKey is the number of increments, for each key the sketch is sized for, after which all counts are halved so old use
+	// is forgotten
+	sketchSamplesPerKey = 10
+)
+
+// countMinSketch estimates how often keys have been used in a fixed amount of memory.  Counts are halved periodically so keys used often in
+// the past, but not recently, are forgotten.  countMinSketch is not thread safe.
+type countMinSketch[K comparable] struct {
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	seed       maphash.Seed
+	increments int
+	sampleSize int
+}
+
+// newCountMinSketch returns a sketch sized to estimate the frequency of the capacity most frequently used keys
+func newCountMinSketch[K comparable](capacity int) *countMinSketch[K] {
+	capacity = max(capacity, 1)
+	width := 1 << bits.Len(uint(capacity*sketchCountersPerKey-1))
+	s := &countMinSketch[K]{
+		mask:       uint64(width - 1),
+		seed:       maphash.MakeSeed(),
+		sampleSize: capacity * sketchSamplesPerKey,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// increment counts a use of the key
+func (s *countMinSketch[K]) increment(key K) {
+	hash := s.hash(key)
+	for i := range s.rows {
+		index := s.index(hash, i)
+		if s.rows[i][index] < sketchMaxCount {
+			s.rows[i][index]++
+		}
+	}
+
+	s.increments++
+	if s.increments >= s.sampleSize {
+		s.age()
+	}
+}
+
+// estimate returns the estimated number of times the key has been used
+func (s *countMinSketch[K]) estimate(key K) int {
+	hash := s.hash(key)
+	estimate := sketchMaxCount
+	for i := range s.rows {
+		estimate = min(estimate, int(s.rows[i][s.index(hash, i)]))
+	}
+	return estimate
+}
+
+// age halves all counts
+func (s *countMinSketch[K]) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.increments /= 2
+}
+
+// index returns the index of the counter for the hash in the row
+func (s *countMinSketch[K]) index(hash uint64, row int) uint64 {
+	return (hash + uint64(row)*(hash>>32|1)) & s.mask
+}
+
+// hash returns the hash of the key
+func (s *countMinSketch[K]) hash(key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(s.seed, k)
+	case int:
+		return mix(uint64(k))
+	case int32:
+		return mix(uint64(k))
+	case int64:
+		return mix(uint64(k))
+	case uint:
+		return mix(uint64(k))
+	case uint32:
+		return mix(uint64(k))
+	case uint64:
+		return mix(k)
+	default:
+		return maphash.String(s.seed, fmt.Sprintf("%#v", k))
+	}
+}
+
+// mix spreads the bits of an integer key over its hash
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountMinSketch_Estimate_CountsIncrements(t *testing.T) {
+	// setup
+	s := newCountMinSketch[string](100)
+
+	// test
+	for i := 0; i < 5; i++ {
+		s.increment("a")
+	}
+	s.increment("b")
+
+	// assert
+	assert.GreaterOrEqual(t, s.estimate("a"), 5)
+	assert.GreaterOrEqual(t, s.estimate("b"), 1)
+	assert.Less(t, s.estimate("b"), s.estimate("a"))
+}
+
+func TestCountMinSketch_Estimate_SaturatesAtMaxCount(t *testing.T) {
+	// setup
+	s := newCountMinSketch[int](100)
+
+	// test
+	for i := 0; i < 100; i++ {
+		s.increment(1)
+	}
+
+	// assert
+	assert.Equal(t, sketchMaxCount, s.estimate(1))
+}
+
+func TestCountMinSketch_Age_HalvesCounts(t *testing.T) {
+	// setup
+	s := newCountMinSketch[int](100)
+	for i := 0; i < 8; i++ {
+		s.increment(1)
+	}
+
+	// test
+	s.age()
+
+	// assert
+	assert.Equal(t, 4, s.estimate(1))
+}
+
+type sketchKey struct {
+	a int
+	b string
+}
+
+func TestCountMinSketch_Estimate_StructKeys(t *testing.T) {
+	// setup
+	s := newCountMinSketch[sketchKey](100)
+
+	// test
+	s.increment(sketchKey{a: 1, b: "x"})
+	s.increment(sketchKey{a: 1, b: "x"})
+
+	// assert
+	assert.GreaterOrEqual(t, s.estimate(sketchKey{a: 1, b: "x"}), 2)
+}
@@ -7,6 +7,7 @@
 package storage
 
 import (
+	"container/heap"
 	"context"
 	"math"
 	"sync"
@@ -18,6 +19,9 @@ type FifoMapCache[K comparable, V any] struct {
 	currentPartitionId  uint64
 	currentPartitionMux *sync.RWMutex
 	valuePartitionIndex *SafeMap[K, uint64]
+	expiries            *SafeMap[K, time.Time]
+	expiryQueue         *expiryHeap[K]
+	expiryMux           *sync.Mutex
 	ctx                 context.Context
 	partitionCapacity   int
 	maxPartitions       int
@@ -30,6 +34,7 @@ type numPartitionCalculator func(capacity int) (int, int)
 type fifoMapConfiguration struct {
 	numPartitionCalculator numPartitionCalculator
 	sweepFrequency         time.Duration
+	defaultTTL             time.Duration
 }
 
 type fifoInitializationOption func(configuration *fifoMapConfiguration)
@@ -51,6 +56,9 @@ func NewFifoMapCache[K comparable, V any](ctx context.Context, capacity int, opt
 		ctx:                 ctx,
 		partitionCapacity:   partitionLength,
 		valuePartitionIndex: NewSafeMap[K, uint64](0),
+		expiries:            NewSafeMap[K, time.Time](0),
+		expiryQueue:         &expiryHeap[K]{},
+		expiryMux:           &sync.Mutex{},
 		currentPartitionMux: &sync.RWMutex{},
 		sweepingMux:         &sync.Mutex{},
 		config:              cfg,
@@ -78,8 +86,11 @@ func (f *FifoMapCache[K, V]) Capacity() int {
 	return f.maxPartitions * f.partitionCapacity
 }
 
-// Contains returns true if the key of type K is in the map
+// Contains returns true if the key of type K is in the map and has not expired
 func (f *FifoMapCache[K, V]) Contains(key K) bool {
+	if f.expired(key, time.Now()) {
+		return false
+	}
 	if partitionId := f.valuePartitionIndex.Get(key); partitionId > 0 {
 		partition, _ := f.partitions.Peek(partitionId)
 		if partition != nil {
@@ -89,8 +100,11 @@ func (f *FifoMapCache[K, V]) Contains(key K) bool {
 	return false
 }
 
-// Get returns the value of type V for the key of type K.  If the key is not found, the zero value of V is returned.
+// Get returns the value of type V for the key of type K.  If the key is not found or has expired, the zero value of V is returned.
 func (f *FifoMapCache[K, V]) Get(key K) (value V) {
+	if f.expired(key, time.Now()) {
+		return
+	}
 	if partitionId := f.valuePartitionIndex.Get(key); partitionId > 0 {
 		partition, _ := f.partitions.Peek(partitionId)
 		if partition != nil {
@@ -100,19 +114,41 @@ func (f *FifoMapCache[K, V]) Get(key K) (value V) {
 	return
 }
 
-// Set sets the value of type V for the key of type K.
+// Set sets the value of type V for the key of type K.  The value expires after the default TTL, if the cache has one.
 func (f *FifoMapCache[K, V]) Set(key K, value V) {
-	var partitionId uint64
+	f.SetWithTTL(key, value, f.config.defaultTTL)
+}
+
+// SetWithTTL sets the value of type V for the key of type K, expiring after the ttl.  A ttl of zero or less never expires.
+func (f *FifoMapCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	f.set(key, value, expires)
+}
+
+// set sets the value of type V for the key of type K, expiring at the time unless it is the zero time.  The expiry and value are set
+// under the expiry lock so a sweep does not remove the value when an expired key is set again.
+func (f *FifoMapCache[K, V]) set(key K, value V, expires time.Time) {
+	var partition *SafeMap[K, V]
 	// if key exists, update value
-	if partitionId = f.valuePartitionIndex.Get(key); partitionId > 0 {
-		partition, _ := f.partitions.Peek(partitionId)
-		if partition != nil {
-			partition.Set(key, value)
-			return
-		}
+	partitionId := f.valuePartitionIndex.Get(key)
+	if partitionId > 0 {
+		partition, _ = f.partitions.Peek(partitionId)
+	}
+	if partition == nil {
+		partition, partitionId = f.getCurrentPartition()
 	}
 
-	partition, partitionId := f.getCurrentPartition()
+	f.expiryMux.Lock()
+	defer f.expiryMux.Unlock()
+	if expires.IsZero() {
+		f.expiries.Delete(key)
+	} else {
+		f.expiries.Set(key, expires)
+		heap.Push(f.expiryQueue, expiryEntry[K]{key: key, expires: expires})
+	}
 	partition.Set(key, value)
 	f.valuePartitionIndex.Set(key, partitionId)
 }
@@ -124,9 +160,10 @@ func (f *FifoMapCache[K, V]) Delete(key K) {
 			partition.Delete(key)
 		}
 	}
+	f.expiries.Delete(key)
 }
 
-// Len returns the length of the map
+// Len returns the number of values in the map that have not expired
 func (f *FifoMapCache[K, V]) Len() int {
 	return len(f.Keys())
 }
@@ -137,24 +174,38 @@ func (f *FifoMapCache[K, V]) Clear() {
 	defer f.currentPartitionMux.Unlock()
 	f.partitions = NewGenericStack[*SafeMap[K, V]](f.maxPartitions)
 	f.valuePartitionIndex = NewSafeMap[K, uint64](0)
+	f.expiryMux.Lock()
+	f.expiries = NewSafeMap[K, time.Time](0)
+	f.expiryQueue = &expiryHeap[K]{}
+	f.expiryMux.Unlock()
 	newPartition := NewSafeMap[K, V](f.partitionCapacity)
 	f.currentPartitionId = f.partitions.Push(newPartition)
 }
 
-// Keys returns a slice of keys
+// Keys returns a slice of keys that have not expired
 func (f *FifoMapCache[K, V]) Keys() []K {
+	now := time.Now()
 	keys := make([]K, 0, f.partitionCapacity*f.partitions.Len())
 	for _, partition := range f.partitions.Values() {
-		keys = append(keys, partition.Keys()...)
+		for _, key := range partition.Keys() {
+			if !f.expired(key, now) {
+				keys = append(keys, key)
+			}
+		}
 	}
 	return keys
 }
 
-// Values returns a slice of values
+// Values returns a slice of values that have not expired
 func (f *FifoMapCache[K, V]) Values() []V {
+	now := time.Now()
 	values := make([]V, 0, f.partitionCapacity*f.partitions.Len())
 	for _, partition := range f.partitions.Values() {
-		values = append(values, partition.Values()...)
+		for key, value := range partition.CopyToMap() {
+			if !f.expired(key, now) {
+				values = append(values, value)
+			}
+		}
 	}
 	return values
 }
@@ -178,9 +229,13 @@ func (f *FifoMapCache[K, V]) Resize(capacity int) {
 			if partition == nil {
 				break
 			}
+			now := time.Now()
 			for _, key := range partition.Keys() {
+				if f.expired(key, now) {
+					continue
+				}
 				value := partition.Get(key)
-				f.Set(key, value)
+				f.set(key, value, f.expiries.Get(key))
 			}
 			f.Sweep()
 		}
@@ -203,7 +258,7 @@ func (f *FifoMapCache[K, V]) getCurrentPartition() (*SafeMap[K, V], uint64) {
 	return newPartition, f.currentPartitionId
 }
 
-// sweep removes partitions from the stack if the number of partitions exceeds the maxPartitions
+// sweep removes partitions from the stack if the number of partitions exceeds the maxPartitions, and removes expired values
 func (f *FifoMapCache[K, V]) Sweep() {
 	// restrict to single sweep at a time
 	f.sweepingMux.Lock()
@@ -214,9 +269,78 @@ func (f *FifoMapCache[K, V]) Sweep() {
 	if f.partitions.Len() > f.maxPartitions {
 		numToPop := f.partitions.Len() - f.maxPartitions
 		for i := 0; i < numToPop; i++ {
-			f.partitions.Pop()
+			if partition := f.partitions.Pop(); partition != nil {
+				for _, key := range partition.Keys() {
+					f.expiries.Delete(key)
+				}
+			}
 		}
 	}
+
+	f.sweepExpired(time.Now())
+}
+
+// sweepExpired removes the keys that have expired by now, taking them from the expiry queue in the order they expire.  The expiry queue
+// holds an entry each time a key is set with a ttl, so the expiry of each key is checked and the key removed under the expiry lock, and a
+// key set again since its entry was queued is not removed.
+func (f *FifoMapCache[K, V]) sweepExpired(now time.Time) {
+	f.expiryMux.Lock()
+	defer f.expiryMux.Unlock()
+	for f.expiryQueue.Len() > 0 && !(*f.expiryQueue)[0].expires.After(now) {
+		entry := heap.Pop(f.expiryQueue).(expiryEntry[K])
+		if f.expired(entry.key, now) {
+			f.remove(entry.key)
+		}
+	}
+}
+
+// remove removes the key of type K from the partition holding it, the partition index and the expiries.  expiryMux must be held.
+func (f *FifoMapCache[K, V]) remove(key K) {
+	if partitionId := f.valuePartitionIndex.Get(key); partitionId > 0 {
+		if partition, _ := f.partitions.Peek(partitionId); partition != nil {
+			partition.Delete(key)
+		}
+	}
+	f.valuePartitionIndex.Delete(key)
+	f.expiries.Delete(key)
+}
+
+// expired returns true if the key of type K has a ttl that has passed
+func (f *FifoMapCache[K, V]) expired(key K, now time.Time) bool {
+	expires := f.expiries.Get(key)
+	return !expires.IsZero() && !expires.After(now)
+}
+
+// expiryEntry is the time a key of type K set with a ttl expires
+type expiryEntry[K comparable] struct {
+	key     K
+	expires time.Time
+}
+
+// Implements container/heap, with pop returning the entry expiring first
+type expiryHeap[K comparable] []expiryEntry[K]
+
+func (h expiryHeap[K]) Len() int {
+	return len(h)
+}
+func (h expiryHeap[K]) Less(i, j int) bool {
+	return h[i].expires.Before(h[j].expires)
+}
+func (h expiryHeap[K]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+// Push pushes x, which must be an expiryEntry[K], to the heap
+func (h *expiryHeap[K]) Push(x any) {
+	*h = append(*h, x.(expiryEntry[K]))
+}
+
+// Pop pops and returns the last expiryEntry[K] of the heap
+func (h *expiryHeap[K]) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
 }
 
 // default numPartitionCalculator, creates a balance between number of partitions and the size of each partition.
@@ -256,4 +380,12 @@ func WithSweepFrequency(frequency time.Duration) fifoInitializationOption {
 	}
 }
 
+// WithDefaultTTL expires values set with Set after the ttl.  Expired values are no longer returned by the cache and are removed when the
+// cache is swept.  By default values do not expire.
+func WithDefaultTTL(ttl time.Duration) fifoInitializationOption {
+	return func(configuration *fifoMapConfiguration) {
+		configuration.defaultTTL = ttl
+	}
+}
+
 //endregion
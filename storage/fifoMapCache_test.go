@@ -594,3 +594,172 @@ func TestFifoMapCache_Capacity(t *testing.T) {
 	// assert
 	assert.Equal(t, 25, capacity, "Expected capacity to be 25")
 }
+
+func TestFifoMapCache_SetWithTTL_ExpiredValueIsInvisible(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+	m.Set(2, 2)
+
+	// test
+	time.Sleep(time.Millisecond * 30)
+
+	// assert
+	assert.Equal(t, 0, m.Get(1), "Expected expired value to be zero value")
+	assert.False(t, m.Contains(1), "Expected expired key not to be contained")
+	assert.Equal(t, []int{2}, m.Keys(), "Expected expired key not to be returned")
+	assert.Equal(t, []int{2}, m.Values(), "Expected expired value not to be returned")
+	assert.Equal(t, 2, m.Get(2), "Expected value without ttl to be returned")
+}
+
+func TestFifoMapCache_WithDefaultTTL_SetValueExpires(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100, WithDefaultTTL(time.Millisecond*20))
+	m.Set(1, 1)
+	m.SetWithTTL(2, 2, time.Minute)
+
+	// test
+	before := m.Get(1)
+	time.Sleep(time.Millisecond * 30)
+
+	// assert
+	assert.Equal(t, 1, before, "Expected value to be returned before it expires")
+	assert.False(t, m.Contains(1), "Expected value to expire after default ttl")
+	assert.True(t, m.Contains(2), "Expected value set with ttl to override default ttl")
+}
+
+func TestFifoMapCache_SetWithTTL_SetAgainWithoutTTL_DoesNotExpire(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+
+	// test
+	m.Set(1, 2)
+	time.Sleep(time.Millisecond * 30)
+
+	// assert
+	assert.Equal(t, 2, m.Get(1), "Expected value set without ttl not to expire")
+}
+
+func TestFifoMapCache_Sweep_RemovesExpiredValues(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+	m.Set(2, 2)
+	time.Sleep(time.Millisecond * 30)
+
+	// test
+	m.Sweep()
+
+	// assert
+	currPartition, _ := m.partitions.Peek(m.currentPartitionId)
+	assert.False(t, currPartition.Has(1), "Expected expired value to be removed from partition")
+	assert.False(t, m.valuePartitionIndex.Has(1), "Expected expired key to be removed from index")
+	assert.Equal(t, 0, m.expiries.Len(), "Expected expiry to be removed")
+	assert.True(t, currPartition.Has(2), "Expected value without ttl to remain")
+}
+
+func TestFifoMapCache_Sweep_ExpiredKeySetAgain_KeepsValue(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 1000)
+
+	for round := 0; round < 100; round++ {
+		for i := 0; i < 100; i++ {
+			m.SetWithTTL(i, round, time.Nanosecond)
+		}
+
+		// test
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.Sweep()
+		}()
+		for i := 0; i < 100; i++ {
+			m.Set(i, round+1)
+		}
+		<-done
+
+		// assert
+		for i := 0; i < 100; i++ {
+			if !assert.Equal(t, round+1, m.Get(i), "Expected value set again while sweeping to remain") {
+				return
+			}
+		}
+	}
+}
+
+func TestFifoMapCache_Len_ExcludesExpiredValues(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+	m.SetWithTTL(2, 2, time.Minute)
+	m.Set(3, 3)
+
+	// test
+	time.Sleep(time.Millisecond * 30)
+
+	// assert
+	assert.Equal(t, 2, m.Len(), "Expected expired value not to be counted before it is swept")
+}
+
+func TestFifoMapCache_Sweep_KeepsExpiriesNotDue(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+	m.SetWithTTL(2, 2, time.Minute)
+	time.Sleep(time.Millisecond * 30)
+
+	// test
+	m.Sweep()
+
+	// assert
+	assert.Equal(t, 1, m.expiryQueue.Len(), "Expected only the expiry not yet due to remain queued")
+	assert.Equal(t, 1, m.expiries.Len(), "Expected only the expiry not yet due to remain")
+	assert.True(t, m.Contains(2), "Expected value not yet expired to remain")
+}
+
+func TestFifoMapCache_WithSweepFrequency_RemovesExpiredValues(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100, WithSweepFrequency(time.Millisecond*10))
+
+	// test
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return !m.valuePartitionIndex.Has(1)
+	}, time.Second, time.Millisecond*5, "Expected expired value to be removed by sweep")
+}
+
+func TestFifoMapCache_Resize_KeepsTTL(t *testing.T) {
+	// setup
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m := NewFifoMapCache[int, int](ctx, 100)
+	m.SetWithTTL(1, 1, time.Millisecond*20)
+	m.Set(2, 2)
+
+	// test
+	m.Resize(200)
+	time.Sleep(time.Millisecond * 30)
+
+	// assert
+	assert.False(t, m.Contains(1), "Expected value to expire after resize")
+	assert.True(t, m.Contains(2), "Expected value without ttl to remain after resize")
+}
@@ -0,0 +1,190 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lfuEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	frequency int
+}
+
+// LfuCache is a thread safe cache of K, V with a maximum capacity.  When the cache is full, the least frequently used values are evicted,
+// the least recently used first when values have been used equally often.
+type LfuCache[K comparable, V any] struct {
+	mux          *sync.Mutex
+	elements     map[K]*list.Element
+	frequencies  map[int]*list.List
+	minFrequency int
+	capacity     int
+}
+
+// NewLfuCache returns an initialized reference to an LfuCache of K, V holding up to capacity values
+func NewLfuCache[K comparable, V any](capacity int) *LfuCache[K, V] {
+	return &LfuCache[K, V]{
+		mux:         &sync.Mutex{},
+		elements:    make(map[K]*list.Element, max(capacity, 0)),
+		frequencies: map[int]*list.List{},
+		capacity:    max(capacity, 1),
+	}
+}
+
+// Capacity returns the maximum number of values the cache holds
+func (c *LfuCache[K, V]) Capacity() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.capacity
+}
+
+// Contains returns true if the key of type K is in the cache, without counting it as used
+func (c *LfuCache[K, V]) Contains(key K) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	_, ok := c.elements[key]
+	return ok
+}
+
+// Get returns the value of type V for the key of type K, counting it as used.  If the key is not found, the zero value of V is returned.
+func (c *LfuCache[K, V]) Get(key K) (value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if element, ok := c.elements[key]; ok {
+		return c.use(element).value
+	}
+	return
+}
+
+// Set sets the value of type V for the key of type K, counting it as used and evicting the least frequently used value if the cache is full
+func (c *LfuCache[K, V]) Set(key K, value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if element, ok := c.elements[key]; ok {
+		c.use(element).value = value
+		return
+	}
+
+	if len(c.elements) >= c.capacity {
+		c.evict(len(c.elements) - c.capacity + 1)
+	}
+	c.elements[key] = c.frequency(1).PushFront(&lfuEntry[K, V]{key: key, value: value, frequency: 1})
+	c.minFrequency = 1
+}
+
+// Delete deletes the key of type K from the cache
+func (c *LfuCache[K, V]) Delete(key K) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if element, ok := c.elements[key]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of values in the cache
+func (c *LfuCache[K, V]) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return len(c.elements)
+}
+
+// Clear removes all the values from the cache
+func (c *LfuCache[K, V]) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.elements = make(map[K]*list.Element, c.capacity)
+	c.frequencies = map[int]*list.List{}
+	c.minFrequency = 0
+}
+
+// Keys returns a slice of keys
+func (c *LfuCache[K, V]) Keys() []K {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	keys := make([]K, 0, len(c.elements))
+	for key := range c.elements {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Values returns a slice of values
+func (c *LfuCache[K, V]) Values() []V {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	values := make([]V, 0, len(c.elements))
+	for _, element := range c.elements {
+		values = append(values, element.Value.(*lfuEntry[K, V]).value)
+	}
+	return values
+}
+
+// Resize changes the capacity of the cache, evicting the least frequently used values if the cache holds more than the capacity
+func (c *LfuCache[K, V]) Resize(capacity int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.capacity = max(capacity, 1)
+	if len(c.elements) > c.capacity {
+		c.evict(len(c.elements) - c.capacity)
+	}
+}
+
+// use counts the entry as used, moving it to the front of the list of its new frequency.  mux must be held.
+func (c *LfuCache[K, V]) use(element *list.Element) *lfuEntry[K, V] {
+	entry := element.Value.(*lfuEntry[K, V])
+	c.remove(element)
+	if c.minFrequency == entry.frequency && c.frequencies[entry.frequency] == nil {
+		c.minFrequency++
+	}
+	entry.frequency++
+	c.elements[entry.key] = c.frequency(entry.frequency).PushFront(entry)
+	return entry
+}
+
+// remove removes the element from the cache.  mux must be held.
+func (c *LfuCache[K, V]) remove(element *list.Element) {
+	entry := element.Value.(*lfuEntry[K, V])
+	entries := c.frequencies[entry.frequency]
+	entries.Remove(element)
+	if entries.Len() == 0 {
+		delete(c.frequencies, entry.frequency)
+	}
+	delete(c.elements, entry.key)
+}
+
+// evict removes the count least frequently used values.  mux must be held.
+func (c *LfuCache[K, V]) evict(count int) {
+	for ; count > 0 && len(c.elements) > 0; count-- {
+		if c.frequencies[c.minFrequency] == nil {
+			c.minFrequency = c.lowestFrequency()
+		}
+		c.remove(c.frequencies[c.minFrequency].Back())
+	}
+}
+
+// frequency returns the list of entries used the number of times, most recently used first.  mux must be held.
+func (c *LfuCache[K, V]) frequency(frequency int) *list.List {
+	entries, ok := c.frequencies[frequency]
+	if !ok {
+		entries = list.New()
+		c.frequencies[frequency] = entries
+	}
+	return entries
+}
+
+// lowestFrequency returns the lowest number of times an entry in the cache has been used.  mux must be held.
+func (c *LfuCache[K, V]) lowestFrequency() int {
+	lowest := 0
+	for frequency := range c.frequencies {
+		if lowest == 0 || frequency < lowest {
+			lowest = frequency
+		}
+	}
+	return lowest
+}
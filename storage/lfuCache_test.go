@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLfuCache_Set_BeyondCapacity_EvictsLeastFrequentlyUsed(t *testing.T) {
+	// setup
+	c := NewLfuCache[int, int](3)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+	c.Get(1)
+	c.Get(3)
+
+	// test
+	c.Set(4, 4)
+
+	// assert
+	assert.False(t, c.Contains(2), "Expected least frequently used key to be evicted")
+	assert.ElementsMatch(t, []int{1, 3, 4}, c.Keys())
+}
+
+func TestLfuCache_Set_EquallyUsed_EvictsLeastRecentlyUsed(t *testing.T) {
+	// setup
+	c := NewLfuCache[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// test
+	c.Set(3, 3)
+
+	// assert
+	assert.False(t, c.Contains(1), "Expected least recently used key to be evicted")
+	assert.True(t, c.Contains(2), "Expected key 2 to remain")
+}
+
+func TestLfuCache_Delete_LeastFrequentlyUsed_EvictsNextLeastFrequentlyUsed(t *testing.T) {
+	// setup
+	c := NewLfuCache[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	for i := 0; i < 3; i++ {
+		c.Get(2)
+	}
+	c.Get(1)
+
+	// test
+	c.Delete(1)
+	c.Set(3, 3)
+	c.Get(3)
+	c.Set(4, 4)
+
+	// assert
+	assert.True(t, c.Contains(2), "Expected most frequently used key to remain")
+	assert.False(t, c.Contains(3), "Expected least frequently used key to be evicted")
+	assert.True(t, c.Contains(4), "Expected new key to be set")
+}
+
+func TestLfuCache_Resize_EvictsLeastFrequentlyUsed(t *testing.T) {
+	// setup
+	c := NewLfuCache[int, int](4)
+	for i := 1; i <= 4; i++ {
+		c.Set(i, i)
+		for j := 0; j < i; j++ {
+			c.Get(i)
+		}
+	}
+
+	// test
+	c.Resize(2)
+
+	// assert
+	assert.ElementsMatch(t, []int{3, 4}, c.Keys(), "Expected most frequently used keys to remain")
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LruCache is a thread safe cache of K, V with a maximum capacity.  When the cache is full, the least recently used values are evicted.
+type LruCache[K comparable, V any] struct {
+	mux      *sync.Mutex
+	entries  *lruList[K, V]
+	capacity int
+}
+
+// NewLruCache returns an initialized reference to an LruCache of K, V holding up to capacity values
+func NewLruCache[K comparable, V any](capacity int) *LruCache[K, V] {
+	return &LruCache[K, V]{
+		mux:      &sync.Mutex{},
+		entries:  newLruList[K, V](capacity),
+		capacity: max(capacity, 1),
+	}
+}
+
+// Capacity returns the maximum number of values the cache holds
+func (c *LruCache[K, V]) Capacity() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.capacity
+}
+
+// Contains returns true if the key of type K is in the cache, without marking it used
+func (c *LruCache[K, V]) Contains(key K) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.entries.contains(key)
+}
+
+// Get returns the value of type V for the key of type K, marking it the most recently used.  If the key is not found, the zero value of V
+// is returned.
+func (c *LruCache[K, V]) Get(key K) (value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	value, _ = c.entries.get(key)
+	return
+}
+
+// Set sets the value of type V for the key of type K, marking it the most recently used and evicting the least recently used value if the
+// cache is full
+func (c *LruCache[K, V]) Set(key K, value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.entries.set(key, value)
+	c.evict()
+}
+
+// Delete deletes the key of type K from the cache
+func (c *LruCache[K, V]) Delete(key K) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.entries.remove(key)
+}
+
+// Len returns the number of values in the cache
+func (c *LruCache[K, V]) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.entries.len()
+}
+
+// Clear removes all the values from the cache
+func (c *LruCache[K, V]) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.entries = newLruList[K, V](c.capacity)
+}
+
+// Keys returns a slice of keys, from the most to the least recently used
+func (c *LruCache[K, V]) Keys() []K {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.entries.keys()
+}
+
+// Values returns a slice of values, from the most to the least recently used
+func (c *LruCache[K, V]) Values() []V {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.entries.values()
+}
+
+// Resize changes the capacity of the cache, evicting the least recently used values if the cache holds more than the capacity
+func (c *LruCache[K, V]) Resize(capacity int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.capacity = max(capacity, 1)
+	c.evict()
+}
+
+// evict removes the least recently used values while the cache holds more than its capacity.  mux must be held.
+func (c *LruCache[K, V]) evict() {
+	for c.entries.len() > c.capacity {
+		c.entries.removeOldest()
+	}
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// lruList is a map of K, V ordered from the most to the least recently used.  lruList is not thread safe.
+type lruList[K comparable, V any] struct {
+	order    *list.List
+	elements map[K]*list.Element
+}
+
+func newLruList[K comparable, V any](initialCapacity int) *lruList[K, V] {
+	return &lruList[K, V]{
+		order:    list.New(),
+		elements: make(map[K]*list.Element, max(initialCapacity, 0)),
+	}
+}
+
+// contains returns true if the key is in the list
+func (l *lruList[K, V]) contains(key K) bool {
+	_, ok := l.elements[key]
+	return ok
+}
+
+// get returns the value for the key, moving it to the front of the list
+func (l *lruList[K, V]) get(key K) (value V, ok bool) {
+	element, ok := l.elements[key]
+	if !ok {
+		return
+	}
+	l.order.MoveToFront(element)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// set sets the value for the key, moving it to the front of the list
+func (l *lruList[K, V]) set(key K, value V) {
+	if element, ok := l.elements[key]; ok {
+		element.Value.(*lruEntry[K, V]).value = value
+		l.order.MoveToFront(element)
+		return
+	}
+	l.elements[key] = l.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+}
+
+// remove removes the key from the list, returning its value
+func (l *lruList[K, V]) remove(key K) (value V, ok bool) {
+	element, ok := l.elements[key]
+	if !ok {
+		return
+	}
+	l.order.Remove(element)
+	delete(l.elements, key)
+	return element.Value.(*lruEntry[K, V]).value, true
+}
+
+// oldest returns the least recently used key and value without removing it
+func (l *lruList[K, V]) oldest() (key K, value V, ok bool) {
+	element := l.order.Back()
+	if element == nil {
+		return
+	}
+	entry := element.Value.(*lruEntry[K, V])
+	return entry.key, entry.value, true
+}
+
+// removeOldest removes the least recently used key, returning it and its value
+func (l *lruList[K, V]) removeOldest() (key K, value V, ok bool) {
+	key, value, ok = l.oldest()
+	if ok {
+		l.remove(key)
+	}
+	return
+}
+
+func (l *lruList[K, V]) len() int {
+	return len(l.elements)
+}
+
+func (l *lruList[K, V]) keys() []K {
+	keys := make([]K, 0, len(l.elements))
+	for element := l.order.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(*lruEntry[K, V]).key)
+	}
+	return keys
+}
+
+func (l *lruList[K, V]) values() []V {
+	values := make([]V, 0, len(l.elements))
+	for element := l.order.Front(); element != nil; element = element.Next() {
+		values = append(values, element.Value.(*lruEntry[K, V]).value)
+	}
+	return values
+}
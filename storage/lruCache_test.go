@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLruCache_Set_BeyondCapacity_EvictsLeastRecentlyUsed(t *testing.T) {
+	// setup
+	c := NewLruCache[int, int](3)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Set(3, 3)
+	c.Get(1)
+
+	// test
+	c.Set(4, 4)
+
+	// assert
+	assert.False(t, c.Contains(2), "Expected least recently used key to be evicted")
+	assert.Equal(t, []int{4, 1, 3}, c.Keys(), "Expected keys from most to least recently used")
+}
+
+func TestLruCache_Contains_DoesNotMarkUsed(t *testing.T) {
+	// setup
+	c := NewLruCache[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// test
+	c.Contains(1)
+	c.Set(3, 3)
+
+	// assert
+	assert.False(t, c.Contains(1), "Expected key checked with Contains to be evicted")
+	assert.Equal(t, []int{3, 2}, c.Values(), "Expected values from most to least recently used")
+}
+
+func TestLruCache_Resize_EvictsLeastRecentlyUsed(t *testing.T) {
+	// setup
+	c := NewLruCache[int, int](4)
+	for i := 1; i <= 4; i++ {
+		c.Set(i, i)
+	}
+	c.Get(1)
+
+	// test
+	c.Resize(2)
+
+	// assert
+	assert.Equal(t, []int{1, 4}, c.Keys(), "Expected most recently used keys to remain")
+}
@@ -0,0 +1,214 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"sync"
+)
+
+const (
+	// windowPercent is the percentage of a WTinyLfuCache's capacity holding newly set values
+	windowPercent = 1
+	// protectedPercent is the percentage of a WTinyLfuCache's main segment holding values used since they were admitted
+	protectedPercent = 80
+)
+
+// WTinyLfuCache is a thread safe cache of K, V with a maximum capacity using the W-TinyLFU eviction policy.  New values are set in a small
+// LRU window.  Values evicted from the window are only admitted to the main segment of the cache if their estimated frequency of use is
+// higher than the value the main segment would evict, so a burst of values used once does not flush frequently used values from the cache.
+// The main segment is a segmented LRU: values used again once admitted are protected from eviction by newly admitted values.
+type WTinyLfuCache[K comparable, V any] struct {
+	mux       *sync.Mutex
+	window    *lruList[K, V]
+	probation *lruList[K, V]
+	protected *lruList[K, V]
+	sketch    *countMinSketch[K]
+	capacity  int
+	windowCap int
+	mainCap   int
+	protCap   int
+}
+
+// NewWTinyLfuCache returns an initialized reference to a WTinyLfuCache of K, V holding up to capacity values
+func NewWTinyLfuCache[K comparable, V any](capacity int) *WTinyLfuCache[K, V] {
+	c := &WTinyLfuCache[K, V]{
+		mux:       &sync.Mutex{},
+		window:    newLruList[K, V](0),
+		probation: newLruList[K, V](0),
+		protected: newLruList[K, V](0),
+	}
+	c.size(capacity)
+	return c
+}
+
+// Capacity returns the maximum number of values the cache holds
+func (c *WTinyLfuCache[K, V]) Capacity() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.capacity
+}
+
+// Contains returns true if the key of type K is in the cache, without counting it as used
+func (c *WTinyLfuCache[K, V]) Contains(key K) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.window.contains(key) || c.probation.contains(key) || c.protected.contains(key)
+}
+
+// Get returns the value of type V for the key of type K, counting it as used.  If the key is not found, the zero value of V is returned.
+func (c *WTinyLfuCache[K, V]) Get(key K) (value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.sketch.increment(key)
+	value, _ = c.use(key)
+	return
+}
+
+// Set sets the value of type V for the key of type K, counting it as used.  If the cache is full, either the new value or the value least
+// likely to be used again is evicted.
+func (c *WTinyLfuCache[K, V]) Set(key K, value V) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.sketch.increment(key)
+	if _, ok := c.use(key); ok {
+		c.update(key, value)
+		return
+	}
+
+	c.window.set(key, value)
+	for c.window.len() > c.windowCap {
+		candidate, candidateValue, _ := c.window.removeOldest()
+		c.admit(candidate, candidateValue)
+	}
+}
+
+// Delete deletes the key of type K from the cache
+func (c *WTinyLfuCache[K, V]) Delete(key K) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.window.remove(key)
+	c.probation.remove(key)
+	c.protected.remove(key)
+}
+
+// Len returns the number of values in the cache
+func (c *WTinyLfuCache[K, V]) Len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.window.len() + c.probation.len() + c.protected.len()
+}
+
+// Clear removes all the values from the cache, forgetting how often keys have been used
+func (c *WTinyLfuCache[K, V]) Clear() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.window = newLruList[K, V](0)
+	c.probation = newLruList[K, V](0)
+	c.protected = newLruList[K, V](0)
+	c.sketch = newCountMinSketch[K](c.capacity)
+}
+
+// Keys returns a slice of keys
+func (c *WTinyLfuCache[K, V]) Keys() []K {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	keys := c.window.keys()
+	keys = append(keys, c.protected.keys()...)
+	return append(keys, c.probation.keys()...)
+}
+
+// Values returns a slice of values
+func (c *WTinyLfuCache[K, V]) Values() []V {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	values := c.window.values()
+	values = append(values, c.protected.values()...)
+	return append(values, c.probation.values()...)
+}
+
+// Resize changes the capacity of the cache, evicting the values least likely to be used again if the cache holds more than the capacity.
+// How often keys have been used is forgotten.
+func (c *WTinyLfuCache[K, V]) Resize(capacity int) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.size(capacity)
+	for c.protected.len() > c.protCap {
+		key, value, _ := c.protected.removeOldest()
+		c.probation.set(key, value)
+	}
+	for c.probation.len()+c.protected.len() > c.mainCap {
+		if _, _, ok := c.probation.removeOldest(); !ok {
+			c.protected.removeOldest()
+		}
+	}
+	for c.window.len() > c.windowCap {
+		key, value, _ := c.window.removeOldest()
+		c.admit(key, value)
+	}
+}
+
+// size sets the capacity of the cache and its segments.  mux must be held.
+func (c *WTinyLfuCache[K, V]) size(capacity int) {
+	c.capacity = max(capacity, 1)
+	c.windowCap = max(c.capacity*windowPercent/100, 1)
+	c.mainCap = c.capacity - c.windowCap
+	c.protCap = c.mainCap * protectedPercent / 100
+	c.sketch = newCountMinSketch[K](c.capacity)
+}
+
+// use marks the key as recently used, promoting it to the protected segment if it was on probation.  mux must be held.
+func (c *WTinyLfuCache[K, V]) use(key K) (V, bool) {
+	if value, ok := c.window.get(key); ok {
+		return value, true
+	}
+	if value, ok := c.protected.get(key); ok {
+		return value, true
+	}
+	value, ok := c.probation.remove(key)
+	if !ok {
+		return value, false
+	}
+
+	c.protected.set(key, value)
+	for c.protected.len() > c.protCap {
+		demoted, demotedValue, _ := c.protected.removeOldest()
+		c.probation.set(demoted, demotedValue)
+	}
+	return value, true
+}
+
+// update sets the value of a key in the cache without changing its segment.  mux must be held.
+func (c *WTinyLfuCache[K, V]) update(key K, value V) {
+	switch {
+	case c.window.contains(key):
+		c.window.set(key, value)
+	case c.protected.contains(key):
+		c.protected.set(key, value)
+	default:
+		c.probation.set(key, value)
+	}
+}
+
+// admit adds a value evicted from the window to the main segment if there is room, or if it is estimated to be used more often than the
+// value the main segment would evict.  mux must be held.
+func (c *WTinyLfuCache[K, V]) admit(key K, value V) {
+	if c.probation.len()+c.protected.len() < c.mainCap {
+		c.probation.set(key, value)
+		return
+	}
+
+	victims := c.probation
+	if victims.len() == 0 {
+		victims = c.protected
+	}
+	victim, _, ok := victims.oldest()
+	if !ok || c.sketch.estimate(key) <= c.sketch.estimate(victim) {
+		return
+	}
+	victims.remove(victim)
+	c.probation.set(key, value)
+}
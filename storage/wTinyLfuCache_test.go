@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWTinyLfuCache_SizesSegments(t *testing.T) {
+	// test
+	c := NewWTinyLfuCache[int, int](1000)
+
+	// assert
+	assert.Equal(t, 1000, c.Capacity())
+	assert.Equal(t, 10, c.windowCap, "Expected window to be 1% of capacity")
+	assert.Equal(t, 990, c.mainCap, "Expected main segment to be the rest of capacity")
+	assert.Equal(t, 792, c.protCap, "Expected protected segment to be 80% of main segment")
+}
+
+func TestWTinyLfuCache_Set_ScanDoesNotEvictFrequentlyUsedValues(t *testing.T) {
+	// setup
+	c := NewWTinyLfuCache[int, int](100)
+	for i := 0; i < 50; i++ {
+		c.Set(i, i)
+		for j := 0; j < 3; j++ {
+			c.Get(i)
+		}
+	}
+
+	// test
+	for i := 1000; i < 2000; i++ {
+		c.Set(i, i)
+	}
+
+	// assert
+	for i := 0; i < 50; i++ {
+		assert.True(t, c.Contains(i), "Expected frequently used key %v to remain", i)
+	}
+	assert.LessOrEqual(t, c.Len(), 100)
+}
+
+func TestWTinyLfuCache_Get_PromotesProbationToProtected(t *testing.T) {
+	// setup
+	c := NewWTinyLfuCache[int, int](100)
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// test
+	c.Get(1)
+
+	// assert
+	assert.True(t, c.protected.contains(1), "Expected key used on probation to be protected")
+	assert.True(t, c.window.contains(2), "Expected newest key to be in window")
+}
+
+func TestWTinyLfuCache_Set_ExistingKey_UpdatesValue(t *testing.T) {
+	// setup
+	c := NewWTinyLfuCache[int, int](100)
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// test
+	c.Set(1, 10)
+
+	// assert
+	assert.Equal(t, 10, c.Get(1))
+	assert.Equal(t, 2, c.Len())
+}
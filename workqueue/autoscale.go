@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"time"
+)
+
+// defaultScaleInterval is how often the autoscaler adjusts the number of workers by default
+const defaultScaleInterval = time.Second
+
+type AutoscaleOption func(scaler *autoscaler)
+
+// autoscaler periodically adjusts the number of workers on a queue between a minimum and maximum based on the depth of the queue and how
+// long work waits in the queue
+type autoscaler struct {
+	minWorkers int
+	maxWorkers int
+	interval   time.Duration
+	targetWait time.Duration
+}
+
+// WithAutoscaling adjusts the number of workers on the queue between minWorkers and maxWorkers.  Workers are added while work is waiting in
+// the queue, and removed one at a time while the queue is empty and workers are idle.
+func WithAutoscaling(minWorkers, maxWorkers int, options ...AutoscaleOption) WorkQueueOption {
+	return func(queue *Queue) {
+		scaler := &autoscaler{
+			minWorkers: max(minWorkers, 1),
+			maxWorkers: max(minWorkers, maxWorkers, 1),
+			interval:   defaultScaleInterval,
+		}
+		for _, o := range options {
+			o(scaler)
+		}
+		queue.autoscaler = scaler
+	}
+}
+
+// WithScaleInterval sets how often the autoscaler adjusts the number of workers
+func WithScaleInterval(interval time.Duration) AutoscaleOption {
+	return func(scaler *autoscaler) {
+		scaler.interval = interval
+	}
+}
+
+// WithTargetWait sets the average time work may wait in the queue before the autoscaler adds workers.  By default workers are added whenever
+// work is waiting in the queue.
+func WithTargetWait(wait time.Duration) AutoscaleOption {
+	return func(scaler *autoscaler) {
+		scaler.targetWait = wait
+	}
+}
+
+// clamp limits the worker count to the autoscaler's minimum and maximum
+func (a *autoscaler) clamp(workerCount int) int {
+	return min(max(workerCount, a.minWorkers), a.maxWorkers)
+}
+
+// run adjusts the number of workers on the queue every interval, by the queue's clock, until the queue is stopped
+func (a *autoscaler) run(queue *Queue) {
+	tick := make(chan struct{}, 1)
+	stopTimer := func() bool { return false }
+	defer func() {
+		stopTimer()
+	}()
+
+	started, waitTotal := queue.stats.started.Load(), queue.stats.waitTotal.Load()
+	for {
+		stopTimer = queue.clock.AfterFunc(a.interval, func() {
+			tick <- struct{}{}
+		})
+		select {
+		case <-tick:
+		case <-queue.queueContext.Done():
+			return
+		}
+
+		// average wait of the work started during the interval.  If no work started, waiting work has waited the whole interval.
+		wait := a.interval
+		if s, t := queue.stats.started.Load(), queue.stats.waitTotal.Load(); s > started {
+			wait = time.Duration((t - waitTotal) / int64(s-started))
+			started, waitTotal = s, t
+		}
+
+		// work waiting on a paused queue is not a reason to add workers
+		status := queue.Status()
+		if status.State == PAUSED {
+			continue
+		}
+		stats := status.Stats
+		if workers := a.scale(stats, wait); workers != stats.Workers {
+			queue.SetWorkers(workers)
+		}
+	}
+}
+
+// scale returns the number of workers the queue should have given its stats and the average time work waited over the last interval
+func (a *autoscaler) scale(stats Stats, wait time.Duration) int {
+	switch {
+	case stats.Queued > 0 && wait >= a.targetWait:
+		// add enough workers to take on all the waiting work
+		return a.clamp(stats.Workers + stats.Queued)
+	case stats.Queued == 0 && stats.BusyWorkers < stats.Workers:
+		return a.clamp(stats.Workers - 1)
+	}
+	return a.clamp(stats.Workers)
+}
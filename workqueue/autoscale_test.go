@@ -0,0 +1,47 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoscaler_Scale(t *testing.T) {
+	scaler := &autoscaler{minWorkers: 1, maxWorkers: 8, targetWait: time.Millisecond * 10}
+
+	assert.Equal(t, 5, scaler.scale(Stats{Workers: 2, BusyWorkers: 2, Queued: 3}, time.Millisecond*20))
+	assert.Equal(t, 8, scaler.scale(Stats{Workers: 2, BusyWorkers: 2, Queued: 30}, time.Millisecond*20))
+	assert.Equal(t, 2, scaler.scale(Stats{Workers: 2, BusyWorkers: 2, Queued: 3}, time.Millisecond))
+	assert.Equal(t, 1, scaler.scale(Stats{Workers: 2, BusyWorkers: 0}, 0))
+	assert.Equal(t, 1, scaler.scale(Stats{Workers: 1, BusyWorkers: 0}, 0))
+}
+
+func TestQueue_WithAutoscaling_ScalesWorkers(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(10), WithAutoscaling(1, 4, WithScaleInterval(time.Millisecond*10)))
+	defer q.Stop()
+	release := make(chan struct{})
+	for i := 0; i < 6; i++ {
+		_, _ = q.Enqueue(func() error {
+			<-release
+			return nil
+		})
+	}
+
+	// test & assert
+	assert.Eventually(t, func() bool {
+		return q.Stats().Workers == 4 && q.Stats().BusyWorkers == 4
+	}, time.Second, time.Millisecond*5)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return q.Stats().Workers == 1
+	}, time.Second, time.Millisecond*5)
+}
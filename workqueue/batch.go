@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnqueueBatch queues the context aware work in order, taking the queue's lock once for as much of the batch as the queue has room for.
+// The options are applied to each work item.  If the queue is full, EnqueueBatch blocks until there is room in the queue or ctx is done.
+// The ids of the work queued before any error are returned with the error, so work following the failed work is not queued.
+func (w *Queue) EnqueueBatch(ctx context.Context, work []ContextWork, options ...workOption) ([]uuid.UUID, error) {
+	items := make([]*workItem, len(work))
+	for i, workToDo := range work {
+		items[i] = w.newWorkItem(uuid.New(), workToDo, options...)
+	}
+	return w.enqueueItems(ctx, items, true)
+}
+
+// BatchHandler performs a batch of items at once
+type BatchHandler[T any] func(ctx context.Context, items []T) error
+
+// Batcher collects items into batches performed by a handler on a queue.  A batch is enqueued once it holds the batch size of items, or
+// when the linger window since the first item of the batch was added passes, so the handler receives whatever arrived within the window.
+type Batcher[T any] struct {
+	queue   *Queue
+	handler BatchHandler[T]
+	size    int
+	linger  time.Duration
+	options []workOption
+	mux     *sync.Mutex
+	pending []T
+	batch   uint64
+	// stopTimer stops the linger timer of the current batch
+	stopTimer func() bool
+}
+
+// NewBatcher returns a reference to a Batcher enqueuing batches of up to size items on the queue, performed by the handler.  Batches that
+// have not filled are enqueued once the linger window passes.  The options are applied to each batch enqueued.
+func NewBatcher[T any](queue *Queue, size int, linger time.Duration, handler BatchHandler[T], options ...workOption) *Batcher[T] {
+	size = max(size, 1)
+	return &Batcher[T]{
+		queue:   queue,
+		handler: handler,
+		size:    size,
+		linger:  linger,
+		options: options,
+		mux:     &sync.Mutex{},
+		pending: make([]T, 0, size),
+	}
+}
+
+// Add adds the item to the current batch, enqueuing the batch if it is full.  If the queue is full, Add blocks until there is room in the
+// queue or ctx is done.  An error is returned if the full batch could not be enqueued, in which case the batch is dropped.
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	b.mux.Lock()
+	b.pending = append(b.pending, item)
+	if len(b.pending) < b.size {
+		if len(b.pending) == 1 {
+			batch := b.batch
+			b.stopTimer = b.queue.clock.AfterFunc(b.linger, func() {
+				b.flushBatch(batch)
+			})
+		}
+		b.mux.Unlock()
+		return nil
+	}
+	items := b.take()
+	b.mux.Unlock()
+
+	return b.enqueue(ctx, items)
+}
+
+// Flush enqueues the current batch without waiting for it to fill or for the linger window to pass
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	b.mux.Lock()
+	items := b.take()
+	b.mux.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+	return b.enqueue(ctx, items)
+}
+
+// flushBatch enqueues the batch once its linger window has passed, unless it has already been enqueued.  Errors enqueuing the batch are
+// logged as back pressure, rather than as failed work, as the batch is not enqueued by a caller.
+func (b *Batcher[T]) flushBatch(batch uint64) {
+	b.mux.Lock()
+	if batch != b.batch {
+		b.mux.Unlock()
+		return
+	}
+	items := b.take()
+	b.mux.Unlock()
+
+	if err := b.enqueue(context.Background(), items); err != nil {
+		b.queue.log(context.Background(), LogBackpressure, "batch not enqueued", slog.Int("items", len(items)), slog.Any("error", err))
+	}
+}
+
+// take takes the items of the current batch, starting a new batch.  mux must be held.
+func (b *Batcher[T]) take() []T {
+	if b.stopTimer != nil {
+		b.stopTimer()
+		b.stopTimer = nil
+	}
+	items := b.pending
+	b.pending = make([]T, 0, b.size)
+	b.batch++
+	return items
+}
+
+func (b *Batcher[T]) enqueue(ctx context.Context, items []T) error {
+	_, err := b.queue.EnqueueContext(ctx, func(ctx context.Context) error {
+		return b.handler(ctx, items)
+	}, b.options...)
+	return err
+}
@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_EnqueueBatch_QueuesAllWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2), WithQueueLength(2))
+	defer q.Stop()
+	performed := atomic.Int32{}
+	work := make([]ContextWork, 10)
+	for i := range work {
+		work[i] = func(ctx context.Context) error {
+			performed.Add(1)
+			return nil
+		}
+	}
+
+	// test
+	ids, err := q.EnqueueBatch(context.Background(), work, WithName("bulk"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, ids, 10)
+	assert.Eventually(t, func() bool {
+		return performed.Load() == 10
+	}, time.Second, time.Millisecond)
+	finished, _ := q.FindWork(ids[9])
+	assert.Equal(t, "bulk", finished.Name())
+}
+
+func TestQueue_EnqueueBatch_QueueStopped_ReturnsError(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	q.Stop()
+
+	// test
+	ids, err := q.EnqueueBatch(context.Background(), []ContextWork{func(ctx context.Context) error { return nil }})
+
+	// assert
+	assert.ErrorIs(t, err, ErrQueueStopped)
+	assert.Empty(t, ids)
+}
+
+func TestQueue_EnqueueBatch_ContextDone_ReturnsQueuedIds(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(2))
+	defer q.Stop()
+	q.Pause()
+	work := make([]ContextWork, 3)
+	for i := range work {
+		work[i] = func(ctx context.Context) error { return nil }
+	}
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	ids, err := q.EnqueueBatch(ctx, work)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, ids, 2)
+}
+
+func TestBatcher_Add_EnqueuesFullBatch(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	batches := make(chan []int, 2)
+	b := NewBatcher(q, 3, time.Hour, func(ctx context.Context, items []int) error {
+		batches <- items
+		return nil
+	})
+
+	// test
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, b.Add(context.Background(), i))
+	}
+
+	// assert
+	select {
+	case items := <-batches:
+		assert.Equal(t, []int{1, 2, 3}, items)
+	case <-time.After(time.Second):
+		assert.Fail(t, "full batch was not performed")
+	}
+}
+
+func TestBatcher_Add_EnqueuesPartialBatchAfterLinger(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	mux := &sync.Mutex{}
+	batches := [][]string{}
+	b := NewBatcher(q, 10, time.Millisecond*20, func(ctx context.Context, items []string) error {
+		mux.Lock()
+		defer mux.Unlock()
+		batches = append(batches, items)
+		return nil
+	})
+
+	// test
+	assert.NoError(t, b.Add(context.Background(), "a"))
+	assert.NoError(t, b.Add(context.Background(), "b"))
+
+	// assert
+	assert.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(batches) == 1
+	}, time.Second, time.Millisecond)
+	mux.Lock()
+	defer mux.Unlock()
+	assert.Equal(t, []string{"a", "b"}, batches[0])
+}
+
+func TestBatcher_Add_QueueStopped_LogsBackpressure(t *testing.T) {
+	// setup
+	logger := &recordingLogger{}
+	q := NewQueue(WithWorkers(1), WithLogger(logger))
+	q.Stop()
+	b := NewBatcher(q, 10, time.Millisecond*10, func(ctx context.Context, items []int) error {
+		return nil
+	})
+
+	// test
+	err := b.Add(context.Background(), 1)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return len(logger.Messages()) > 0
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, []loggedMessage{{level: slog.LevelWarn, msg: "batch not enqueued"}}, logger.Messages())
+}
+
+func TestBatcher_Flush_EnqueuesPendingItems(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	batches := make(chan []int, 2)
+	b := NewBatcher(q, 10, time.Hour, func(ctx context.Context, items []int) error {
+		batches <- items
+		return nil
+	})
+	assert.NoError(t, b.Add(context.Background(), 1))
+
+	// test
+	err := b.Flush(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	select {
+	case items := <-batches:
+		assert.Equal(t, []int{1}, items)
+	case <-time.After(time.Second):
+		assert.Fail(t, "flushed batch was not performed")
+	}
+	assert.NoError(t, b.Flush(context.Background()))
+}
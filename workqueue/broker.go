@@ -0,0 +1,243 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultVisibilityTimeout is how long work leased from a broker is hidden from other consumers unless configured otherwise
+const defaultVisibilityTimeout = time.Second * 30
+
+// defaultMaxDeliveries is how many times failed work leased from a broker is delivered unless configured otherwise
+const defaultMaxDeliveries = 10
+
+// defaultRedeliveryBackoff delays the redelivery of failed work leased from a broker unless configured otherwise
+var defaultRedeliveryBackoff = ExponentialBackoff(time.Second, time.Minute)
+
+// brokerRetryDelay is how long a queue waits before leasing again after its broker failed
+const brokerRetryDelay = time.Second
+
+// ErrNoBroker is returned when work is enqueued on a broker by a queue that has no broker
+var ErrNoBroker = errors.New("queue has no broker")
+
+// ErrLeaseExpired is returned when acknowledging, or extending, a lease that has expired or is unknown to the broker.  The leased work may
+// have been delivered to another consumer.
+var ErrLeaseExpired = errors.New("lease expired")
+
+// ErrBrokerClosed is returned by a broker that has been closed
+var ErrBrokerClosed = errors.New("broker closed")
+
+// Lease is work leased from a broker.  The work is hidden from other consumers until the lease expires, after which it is delivered again.
+type Lease struct {
+	Token      uuid.UUID   `json:"token"`
+	Work       *StoredWork `json:"work"`
+	Deliveries int         `json:"deliveries"`
+	Expires    time.Time   `json:"expires"`
+}
+
+// Broker shares work between queues, typically running in different processes.  Work is delivered at least once: work leased by a consumer
+// is delivered again if its lease expires before it is acknowledged, so handlers of brokered work should be idempotent.  Implementations
+// must be safe for concurrent use.
+type Broker interface {
+	// Enqueue adds work to the broker.  The work is not delivered before it is due.
+	Enqueue(ctx context.Context, work *StoredWork) error
+	// Lease blocks until work is available, or ctx is done, returning the due work of highest priority hidden from other consumers for
+	// the visibility timeout
+	Lease(ctx context.Context, visibility time.Duration) (*Lease, error)
+	// Ack removes leased work once it has been performed
+	Ack(ctx context.Context, token uuid.UUID) error
+	// Nack releases leased work that was not performed to be delivered again after the delay
+	Nack(ctx context.Context, token uuid.UUID, delay time.Duration) error
+	// ExtendLease hides leased work from other consumers for the visibility timeout from now
+	ExtendLease(ctx context.Context, token uuid.UUID, visibility time.Duration) error
+}
+
+// EnqueueBroker enqueues work performed by the handler registered with the name on the queue's broker, passing it the payload encoded as
+// JSON, to be performed by whichever queue consuming the broker leases it.  Only the work's name, priority and the time delayed work is due
+// are sent with it, other options are not applied.
+func (w *Queue) EnqueueBroker(ctx context.Context, handler string, payload any, options ...workOption) (uuid.UUID, error) {
+	if w.broker == nil {
+		return uuid.Nil, ErrNoBroker
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("encoding payload for handler %v: %w", handler, err)
+	}
+
+	wi := w.newWorkItem(uuid.New(), nil, options...)
+	err = w.broker.Enqueue(ctx, &StoredWork{
+		Id:         wi.id,
+		Handler:    handler,
+		Name:       wi.name,
+		Priority:   wi.priority,
+		Payload:    data,
+		EnqueuedAt: w.clock.Now(),
+		RunAt:      wi.runAt,
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return wi.id, nil
+}
+
+// consume leases work from the queue's broker while the queue has room for it, until the queue stops
+func (w *Queue) consume() {
+	for w.waitForRoom() {
+		lease, err := w.broker.Lease(w.queueContext, w.visibility)
+		if err != nil {
+			if w.queueContext.Err() != nil {
+				return
+			}
+			w.log(w.queueContext, LogBrokerFailed, "leasing work failed", slog.Any("error", err))
+			w.sleep(brokerRetryDelay)
+			continue
+		}
+		w.perform(lease)
+	}
+}
+
+// waitForRoom blocks until the queue has room for work, returning false if the queue stops
+func (w *Queue) waitForRoom() bool {
+	for {
+		w.queueMux.Lock()
+		full := w.full()
+		changed := w.changed
+		w.queueMux.Unlock()
+		if w.stopped.Load() || w.queueContext.Err() != nil {
+			return false
+		}
+		if !full {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-w.queueContext.Done():
+			return false
+		}
+	}
+}
+
+// sleep waits for the duration by the queue's clock, or until the queue stops
+func (w *Queue) sleep(d time.Duration) {
+	wake := make(chan struct{})
+	stop := w.clock.AfterFunc(d, func() {
+		close(wake)
+	})
+	defer stop()
+	select {
+	case <-wake:
+	case <-w.queueContext.Done():
+	}
+}
+
+// perform queues leased work, extending its lease while it is queued and in process.  Completed work, and work cancelled while the queue
+// is running, is acknowledged.  Work that failed is released to be delivered again after the redelivery backoff, until it has been delivered
+// the maximum number of times when it is dead lettered.  Work skipped or cancelled because the queue stopped is released to be delivered
+// again immediately.  Work for a handler that is not registered is released once its lease would have expired, leaving it to consumers that
+// can perform it.
+func (w *Queue) perform(lease *Lease) {
+	ctx := context.Background()
+	wi, err := w.handlerWork(lease.Work, WithName(lease.Work.Name), WithPriority(lease.Work.Priority))
+	if err != nil {
+		w.log(ctx, LogBrokerFailed, "performing leased work failed", slog.String("id", lease.Work.Id.String()), slog.Any("error", err))
+		w.settle(wi, lease, w.visibility)
+		return
+	}
+
+	stopExtending := w.extendLease(lease)
+	wi.onFinish = func(error) {
+		stopExtending()
+		w.settle(wi, lease, 0)
+	}
+	if _, err := w.enqueue(w.queueContext, wi); err != nil {
+		stopExtending()
+		w.settle(nil, lease, 0)
+	}
+}
+
+// settle acknowledges leased work once it has completed or been cancelled while the queue is running.  Failed work is released to be
+// delivered again after the redelivery backoff, or dead lettered once it has been delivered the maximum number of times.  Other work is
+// released to be delivered again after the delay.
+func (w *Queue) settle(wi *workItem, lease *Lease, delay time.Duration) {
+	ctx := context.Background()
+	state := SKIPPED
+	if wi != nil {
+		state = workState(wi.state.Load())
+	}
+
+	var err error
+	switch {
+	case state == COMPLETED || (wi != nil && state == CANCELLED && w.queueContext.Err() == nil):
+		err = w.broker.Ack(ctx, lease.Token)
+	case state == FAILED && w.maxDeliveries > 0 && lease.Deliveries >= w.maxDeliveries:
+		err = w.deadLetter(lease)
+	case state == FAILED:
+		err = w.broker.Nack(ctx, lease.Token, w.redeliveryDelay(lease.Deliveries))
+	default:
+		err = w.broker.Nack(ctx, lease.Token, delay)
+	}
+	if err != nil {
+		w.log(ctx, LogBrokerFailed, "settling leased work failed", slog.String("id", lease.Work.Id.String()), slog.Any("error", err))
+	}
+}
+
+// deadLetter removes leased work that has failed on its final delivery from the broker, enqueuing it on the dead letter broker if the
+// queue has one.  Work that cannot be enqueued on the dead letter broker is released to be delivered again.
+func (w *Queue) deadLetter(lease *Lease) error {
+	ctx := context.Background()
+	w.log(ctx, LogBrokerFailed, "leased work failed on its final delivery", slog.String("id", lease.Work.Id.String()),
+		slog.Int("deliveries", lease.Deliveries), slog.Bool("deadLettered", w.deadLetterBroker != nil))
+	if w.deadLetterBroker != nil {
+		if err := w.deadLetterBroker.Enqueue(ctx, lease.Work); err != nil {
+			return errors.Join(fmt.Errorf("dead lettering work: %w", err), w.broker.Nack(ctx, lease.Token, w.redeliveryDelay(lease.Deliveries)))
+		}
+	}
+	return w.broker.Ack(ctx, lease.Token)
+}
+
+// extendLease extends the lease every half visibility timeout until the returned function is called or the lease cannot be extended
+func (w *Queue) extendLease(lease *Lease) func() {
+	mux := &sync.Mutex{}
+	stopped := false
+	stopTimer := func() bool { return false }
+
+	var schedule func()
+	schedule = func() {
+		stopTimer = w.clock.AfterFunc(w.visibility/2, func() {
+			if err := w.broker.ExtendLease(context.Background(), lease.Token, w.visibility); err != nil {
+				w.log(context.Background(), LogBrokerFailed, "extending lease failed", slog.String("id", lease.Work.Id.String()),
+					slog.Any("error", err))
+				return
+			}
+			mux.Lock()
+			defer mux.Unlock()
+			if !stopped {
+				schedule()
+			}
+		})
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	schedule()
+	return func() {
+		mux.Lock()
+		defer mux.Unlock()
+		stopped = true
+		stopTimer()
+	}
+}
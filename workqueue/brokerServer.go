@@ -0,0 +1,366 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxLeaseWait is the longest a broker client's lease request waits on the server before the client asks again
+const maxLeaseWait = time.Second
+
+// broker operations sent from a BrokerClient to a BrokerServer
+const (
+	brokerEnqueue = "enqueue"
+	brokerLease   = "lease"
+	brokerAck     = "ack"
+	brokerNack    = "nack"
+	brokerExtend  = "extend"
+)
+
+// broker error codes returned from a BrokerServer to a BrokerClient, mapping to the errors returned by brokers
+const (
+	brokerErrLeaseExpired = "leaseExpired"
+	brokerErrClosed       = "closed"
+	brokerErrTimeout      = "timeout"
+)
+
+// brokerRequest is a request sent from a BrokerClient to a BrokerServer as a line of JSON
+type brokerRequest struct {
+	Id         uint64        `json:"id"`
+	Op         string        `json:"op"`
+	Work       *StoredWork   `json:"work,omitempty"`
+	Token      uuid.UUID     `json:"token,omitempty"`
+	Visibility time.Duration `json:"visibility,omitempty"`
+	Delay      time.Duration `json:"delay,omitempty"`
+	Wait       time.Duration `json:"wait,omitempty"`
+}
+
+// brokerResponse is the response to a brokerRequest
+type brokerResponse struct {
+	Id    uint64 `json:"id"`
+	Lease *Lease `json:"lease,omitempty"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// err returns the error of the response, mapping error codes to the errors returned by brokers
+func (r *brokerResponse) err() error {
+	switch {
+	case r.Code == brokerErrLeaseExpired:
+		return ErrLeaseExpired
+	case r.Code == brokerErrClosed:
+		return ErrBrokerClosed
+	case r.Error != "":
+		return errors.New(r.Error)
+	}
+	return nil
+}
+
+// BrokerServer serves a broker over TCP to queues in other processes connected with DialBroker
+type BrokerServer struct {
+	broker   Broker
+	listener net.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mux      *sync.Mutex
+	conns    map[net.Conn]struct{}
+	wg       *sync.WaitGroup
+}
+
+// ListenBroker serves the broker on the TCP address, such as "127.0.0.1:0" to listen on a free loopback port
+func ListenBroker(address string, broker Broker) (*BrokerServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &BrokerServer{
+		broker:   broker,
+		listener: listener,
+		ctx:      ctx,
+		cancel:   cancel,
+		mux:      &sync.Mutex{},
+		conns:    map[net.Conn]struct{}{},
+		wg:       &sync.WaitGroup{},
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s, nil
+}
+
+// Addr returns the address the server is listening on
+func (s *BrokerServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server, closing the connections of its clients.  Work leased by clients that has not been acknowledged is delivered again
+// once its lease expires.
+func (s *BrokerServer) Close() error {
+	s.cancel()
+	err := s.listener.Close()
+	s.mux.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mux.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// accept serves connections until the server is closed
+func (s *BrokerServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mux.Lock()
+		s.conns[conn] = struct{}{}
+		s.mux.Unlock()
+		s.wg.Add(1)
+		go s.serve(conn)
+	}
+}
+
+// serve handles the requests of a connection concurrently, so a client waiting for a lease does not hold up its other requests
+func (s *BrokerServer) serve(conn net.Conn) {
+	defer s.wg.Done()
+	ctx, cancel := context.WithCancel(s.ctx)
+	requests := &sync.WaitGroup{}
+	defer func() {
+		cancel()
+		requests.Wait()
+		_ = conn.Close()
+		s.mux.Lock()
+		delete(s.conns, conn)
+		s.mux.Unlock()
+	}()
+
+	writeMux := &sync.Mutex{}
+	encoder := json.NewEncoder(conn)
+	decoder := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		req := &brokerRequest{}
+		if err := decoder.Decode(req); err != nil {
+			return
+		}
+		requests.Add(1)
+		go func() {
+			defer requests.Done()
+			resp := s.handle(ctx, req)
+			writeMux.Lock()
+			defer writeMux.Unlock()
+			_ = encoder.Encode(resp)
+		}()
+	}
+}
+
+// handle performs a request on the server's broker
+func (s *BrokerServer) handle(ctx context.Context, req *brokerRequest) *brokerResponse {
+	resp := &brokerResponse{Id: req.Id}
+	var err error
+	switch req.Op {
+	case brokerEnqueue:
+		err = s.broker.Enqueue(ctx, req.Work)
+	case brokerLease:
+		leaseCtx, cancel := context.WithTimeout(ctx, min(req.Wait, maxLeaseWait))
+		resp.Lease, err = s.broker.Lease(leaseCtx, req.Visibility)
+		cancel()
+		if err != nil && ctx.Err() == nil && leaseCtx.Err() != nil {
+			resp.Code = brokerErrTimeout
+			return resp
+		}
+	case brokerAck:
+		err = s.broker.Ack(ctx, req.Token)
+	case brokerNack:
+		err = s.broker.Nack(ctx, req.Token, req.Delay)
+	case brokerExtend:
+		err = s.broker.ExtendLease(ctx, req.Token, req.Visibility)
+	default:
+		err = errors.New("unknown broker operation " + req.Op)
+	}
+
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrLeaseExpired):
+		resp.Code = brokerErrLeaseExpired
+	case errors.Is(err, ErrBrokerClosed), s.ctx.Err() != nil:
+		resp.Code = brokerErrClosed
+	default:
+		resp.Error = err.Error()
+	}
+	return resp
+}
+
+// BrokerClient is a Broker performing requests on a BrokerServer over TCP
+type BrokerClient struct {
+	conn    net.Conn
+	mux     *sync.Mutex
+	encoder *json.Encoder
+	seq     uint64
+	pending map[uint64]chan *brokerResponse
+	closed  bool
+}
+
+// DialBroker connects to the BrokerServer listening on the TCP address
+func DialBroker(address string) (*BrokerClient, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &BrokerClient{
+		conn:    conn,
+		mux:     &sync.Mutex{},
+		encoder: json.NewEncoder(conn),
+		pending: map[uint64]chan *brokerResponse{},
+	}
+	go c.read()
+	return c, nil
+}
+
+// Close closes the connection to the server.  Requests in process return ErrBrokerClosed.
+func (c *BrokerClient) Close() error {
+	return c.conn.Close()
+}
+
+// Enqueue adds work to the server's broker
+func (c *BrokerClient) Enqueue(ctx context.Context, work *StoredWork) error {
+	_, err := c.request(ctx, &brokerRequest{Op: brokerEnqueue, Work: work})
+	return err
+}
+
+// Lease blocks until work is available on the server's broker, or ctx is done.  Work leased as ctx is done is released to be delivered
+// again.
+func (c *BrokerClient) Lease(ctx context.Context, visibility time.Duration) (*Lease, error) {
+	for ctx.Err() == nil {
+		wait := maxLeaseWait
+		if deadline, ok := ctx.Deadline(); ok {
+			wait = min(wait, time.Until(deadline))
+		}
+		resp, err := c.request(ctx, &brokerRequest{Op: brokerLease, Visibility: visibility, Wait: wait})
+		if err != nil {
+			return nil, err
+		}
+		if resp.Code == brokerErrTimeout {
+			continue
+		}
+		return resp.Lease, nil
+	}
+	return nil, ctx.Err()
+}
+
+// Ack removes leased work from the server's broker once it has been performed
+func (c *BrokerClient) Ack(ctx context.Context, token uuid.UUID) error {
+	_, err := c.request(ctx, &brokerRequest{Op: brokerAck, Token: token})
+	return err
+}
+
+// Nack releases leased work that was not performed to be delivered again after the delay
+func (c *BrokerClient) Nack(ctx context.Context, token uuid.UUID, delay time.Duration) error {
+	_, err := c.request(ctx, &brokerRequest{Op: brokerNack, Token: token, Delay: delay})
+	return err
+}
+
+// ExtendLease hides leased work from other consumers for the visibility timeout from now
+func (c *BrokerClient) ExtendLease(ctx context.Context, token uuid.UUID, visibility time.Duration) error {
+	_, err := c.request(ctx, &brokerRequest{Op: brokerExtend, Token: token, Visibility: visibility})
+	return err
+}
+
+// request sends the request to the server and waits for its response, or until ctx is done.  A lease returned after ctx is done is released.
+func (c *BrokerClient) request(ctx context.Context, req *brokerRequest) (*brokerResponse, error) {
+	responses := make(chan *brokerResponse, 1)
+	c.mux.Lock()
+	if c.closed {
+		c.mux.Unlock()
+		return nil, ErrBrokerClosed
+	}
+	c.seq++
+	req.Id = c.seq
+	c.pending[req.Id] = responses
+	err := c.encoder.Encode(req)
+	c.mux.Unlock()
+	if err != nil {
+		c.forget(req.Id)
+		return nil, errors.Join(ErrBrokerClosed, err)
+	}
+
+	select {
+	case resp, ok := <-responses:
+		if !ok {
+			return nil, ErrBrokerClosed
+		}
+		if err := resp.err(); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	case <-ctx.Done():
+		if c.forget(req.Id) {
+			return nil, ctx.Err()
+		}
+		// the response arrived as ctx was done
+		if resp, ok := <-responses; ok && resp.Lease != nil {
+			_ = c.Nack(context.Background(), resp.Lease.Token, 0)
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// forget stops waiting for the response to the request, returning false if the response has already been delivered
+func (c *BrokerClient) forget(id uint64) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	_, ok := c.pending[id]
+	delete(c.pending, id)
+	return ok
+}
+
+// read delivers responses from the server to the requests waiting on them until the connection is closed
+func (c *BrokerClient) read() {
+	decoder := json.NewDecoder(bufio.NewReader(c.conn))
+	for {
+		resp := &brokerResponse{}
+		if err := decoder.Decode(resp); err != nil {
+			break
+		}
+		c.mux.Lock()
+		responses, ok := c.pending[resp.Id]
+		delete(c.pending, resp.Id)
+		c.mux.Unlock()
+		switch {
+		case ok:
+			responses <- resp
+		case resp.Lease != nil:
+			// the request leasing the work stopped waiting, release the work rather than leaving it leased until its visibility timeout.
+			// The release is sent from another go routine as its response is delivered by this one.
+			go func(token uuid.UUID) {
+				_ = c.Nack(context.Background(), token, 0)
+			}(resp.Lease.Token)
+		}
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.closed = true
+	for id, responses := range c.pending {
+		close(responses)
+		delete(c.pending, id)
+	}
+	_ = c.conn.Close()
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBrokerServer_QueuesShareWork(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	server, err := ListenBroker("127.0.0.1:0", b)
+	assert.NoError(t, err)
+	defer server.Close()
+
+	mux := &sync.Mutex{}
+	performed := map[int]int{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "count", func(ctx context.Context, payload testPayload) error {
+		mux.Lock()
+		defer mux.Unlock()
+		performed[payload.Value]++
+		return nil
+	})
+	queues := []*Queue{}
+	for i := 0; i < 2; i++ {
+		client, err := DialBroker(server.Addr())
+		assert.NoError(t, err)
+		defer client.Close()
+		q := NewQueue(WithWorkers(2), WithHandlers(registry), WithBroker(client))
+		defer q.Stop()
+		queues = append(queues, q)
+	}
+
+	// test
+	for i := 0; i < 20; i++ {
+		_, err := queues[i%2].EnqueueBroker(context.Background(), "count", testPayload{Value: i})
+		assert.NoError(t, err)
+	}
+
+	// assert
+	assert.Eventually(t, func() bool {
+		mux.Lock()
+		defer mux.Unlock()
+		return len(performed) == 20 && b.Len() == 0
+	}, time.Second*2, time.Millisecond*5)
+	mux.Lock()
+	defer mux.Unlock()
+	for value, count := range performed {
+		assert.Equal(t, 1, count, "value %v", value)
+	}
+}
+
+func TestBrokerClient_Lease_CancelledWhileWaiting(t *testing.T) {
+	// setup
+	server, err := ListenBroker("127.0.0.1:0", NewMemoryBroker())
+	assert.NoError(t, err)
+	defer server.Close()
+	client, err := DialBroker(server.Addr())
+	assert.NoError(t, err)
+	defer client.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	// test
+	_, err = client.Lease(ctx, time.Minute)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBrokerClient_Lease_LeaseGrantedAfterCancel_IsReleased(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	server, err := ListenBroker("127.0.0.1:0", b)
+	assert.NoError(t, err)
+	defer server.Close()
+	client, err := DialBroker(server.Addr())
+	assert.NoError(t, err)
+	defer client.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		cancel()
+		// the server is still waiting to lease work for the cancelled request
+		_ = b.Enqueue(context.Background(), &StoredWork{Id: uuid.New()})
+	}()
+	_, err = client.Lease(ctx, time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// test
+	leaseCtx, leaseCancel := context.WithTimeout(context.Background(), time.Second)
+	defer leaseCancel()
+	lease, err := client.Lease(leaseCtx, time.Minute)
+
+	// assert
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, lease.Deliveries)
+	}
+}
+
+func TestBrokerClient_Ack_ExpiredLease_ReturnsErrLeaseExpired(t *testing.T) {
+	// setup
+	server, err := ListenBroker("127.0.0.1:0", NewMemoryBroker())
+	assert.NoError(t, err)
+	defer server.Close()
+	client, err := DialBroker(server.Addr())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	// test
+	err = client.Ack(context.Background(), uuid.New())
+
+	// assert
+	assert.ErrorIs(t, err, ErrLeaseExpired)
+}
+
+func TestBrokerClient_ServerClosed_ReturnsErrBrokerClosed(t *testing.T) {
+	// setup
+	server, err := ListenBroker("127.0.0.1:0", NewMemoryBroker())
+	assert.NoError(t, err)
+	client, err := DialBroker(server.Addr())
+	assert.NoError(t, err)
+	defer client.Close()
+
+	// test
+	_ = server.Close()
+	_, err = client.Lease(context.Background(), time.Minute)
+
+	// assert
+	assert.ErrorIs(t, err, ErrBrokerClosed)
+}
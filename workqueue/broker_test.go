@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_WithBroker_PerformsAndAcknowledgesWork(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	registry, values := recordingHandlers()
+	q := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b))
+	defer q.Stop()
+
+	// test
+	_, err := q.EnqueueBroker(context.Background(), "record", testPayload{Value: 1})
+	assert.NoError(t, err)
+	_, err = q.EnqueueBroker(context.Background(), "record", testPayload{Value: 2})
+	assert.NoError(t, err)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return len(values()) == 2 && b.Len() == 0
+	}, time.Second, time.Millisecond*5)
+	assert.ElementsMatch(t, []int{1, 2}, values())
+}
+
+func TestQueue_WithBroker_FailedWorkIsDeliveredAgain(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	attempts := &atomic.Int32{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "flaky", func(ctx context.Context, payload testPayload) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	q := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b), WithRedelivery(3, ConstantBackoff(0)))
+	defer q.Stop()
+
+	// test
+	_, err := q.EnqueueBroker(context.Background(), "flaky", testPayload{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return attempts.Load() == 2 && b.Len() == 0
+	}, time.Second, time.Millisecond*5)
+}
+
+func TestQueue_WithBroker_FailedWorkIsRedeliveredAfterBackoff(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	mux := &sync.Mutex{}
+	attempts := []time.Time{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "flaky", func(ctx context.Context, payload testPayload) error {
+		mux.Lock()
+		defer mux.Unlock()
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			return errors.New("failed")
+		}
+		return nil
+	})
+	q := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b), WithRedelivery(3, ConstantBackoff(time.Millisecond*100)))
+	defer q.Stop()
+
+	// test
+	_, err := q.EnqueueBroker(context.Background(), "flaky", testPayload{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return b.Len() == 0
+	}, time.Second, time.Millisecond*5)
+	mux.Lock()
+	defer mux.Unlock()
+	if assert.Len(t, attempts, 2) {
+		assert.GreaterOrEqual(t, attempts[1].Sub(attempts[0]), time.Millisecond*100)
+	}
+}
+
+func TestQueue_WithBroker_FailedOnFinalDelivery_DeadLettersWork(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	deadLetters := NewMemoryBroker()
+	attempts := &atomic.Int32{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "poison", func(ctx context.Context, payload testPayload) error {
+		attempts.Add(1)
+		return errors.New("failed")
+	})
+	q := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b), WithRedelivery(3, ConstantBackoff(0)),
+		WithDeadLetterBroker(deadLetters))
+	defer q.Stop()
+
+	// test
+	id, err := q.EnqueueBroker(context.Background(), "poison", testPayload{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return b.Len() == 0 && deadLetters.Len() == 1
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, int32(3), attempts.Load())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	lease, err := deadLetters.Lease(ctx, time.Minute)
+	if assert.NoError(t, err) {
+		assert.Equal(t, id, lease.Work.Id)
+	}
+}
+
+func TestQueue_WithBroker_ExtendsLeaseOfLongRunningWork(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	performed := &atomic.Int32{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "slow", func(ctx context.Context, payload testPayload) error {
+		performed.Add(1)
+		time.Sleep(time.Millisecond * 150)
+		return nil
+	})
+	q1 := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b), WithVisibilityTimeout(time.Millisecond*50))
+	defer q1.Stop()
+	q2 := NewQueue(WithWorkers(1), WithHandlers(registry), WithBroker(b), WithVisibilityTimeout(time.Millisecond*50))
+	defer q2.Stop()
+
+	// test
+	_, err := q1.EnqueueBroker(context.Background(), "slow", testPayload{})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return b.Len() == 0
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, int32(1), performed.Load())
+}
+
+func TestQueue_EnqueueBroker_NoBroker_ReturnsError(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+
+	// test
+	_, err := q.EnqueueBroker(context.Background(), "record", testPayload{})
+
+	// assert
+	assert.ErrorIs(t, err, ErrNoBroker)
+}
+
+func TestQueue_WithVisibilityTimeout_NotPositive_KeepsDefault(t *testing.T) {
+	// setup
+	broker := NewMemoryBroker()
+
+	// test
+	q := NewQueue(WithBroker(broker), WithVisibilityTimeout(0))
+	defer q.Stop()
+
+	// assert
+	assert.Equal(t, defaultVisibilityTimeout, q.visibility)
+}
@@ -0,0 +1,38 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time for a queue, determining when delayed work is due, when failed work is retried and when work times out.  Tests may
+// inject a clock they control with WithClock.  Implementations must be safe for concurrent use.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+	// AfterFunc calls f once the duration has passed, returning a function that stops the call, which returns false if f has been called
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+	// WithDeadline returns a copy of the parent context that is done once the deadline passes, with the error context.DeadlineExceeded
+	WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc)
+}
+
+// realClock is the clock of the system, used by queues unless configured otherwise
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
+
+func (realClock) WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	return context.WithDeadline(parent, deadline)
+}
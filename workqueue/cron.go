@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the predefined schedules that can be used in place of a cron expression
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronField describes the values allowed in a field of a cron expression
+type cronField struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: monthNames}
+	// day of week allows 7 as well as 0 for Sunday
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: dayNames}
+)
+
+// CronSchedule is a schedule parsed from a cron expression
+type CronSchedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+	// domAny and dowAny are true if the day of month or day of week field allows every day, as * or */1 do.  If both fields are
+	// restricted, a day matching either field matches the schedule.
+	domAny bool
+	dowAny bool
+	every  time.Duration
+}
+
+// ParseCron parses a cron expression of five space separated fields: minute, hour, day of month, month and day of week.  Fields may be *,
+// a value, a range (1-5), a step (*/15 or 1-30/5), or a comma separated list of any of these.  Months and days of week may be given by their
+// three letter names.  The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly, and @every <duration>, may be
+// used in place of an expression.
+func ParseCron(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid cron expression %q: invalid duration", spec)
+		}
+		return &CronSchedule{every: d}, nil
+	}
+	if expr, ok := cronDescriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, found %v", spec, len(fields))
+	}
+
+	c := &CronSchedule{}
+	var err error
+	for i, f := range []struct {
+		bits  *uint64
+		field cronField
+	}{{&c.minute, minuteField}, {&c.hour, hourField}, {&c.dom, domField}, {&c.month, monthField}, {&c.dow, dowField}} {
+		if *f.bits, err = f.field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", spec, err)
+		}
+	}
+	// Sunday may be given as 0 or 7
+	if c.dow&(1|1<<7) != 0 {
+		c.dow |= 1 | 1<<7
+	}
+	c.domAny = domField.unrestricted(c.dom)
+	c.dowAny = dowField.unrestricted(c.dow)
+	return c, nil
+}
+
+// Next returns the first time the schedule is due after t, or the zero time if the schedule is never due
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	if c.every > 0 {
+		return t.Add(c.every)
+	}
+
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// a schedule not due within five years (such as February 30th) is never due
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parse returns the bits set for the values of the field in the expression
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepExpr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %v field", stepExpr, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rangeExpr != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if low, err = f.value(lowExpr); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = f.value(highExpr); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %v field", rangeExpr, f.name)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// unrestricted returns true if the bits include every value of the field
+func (f cronField) unrestricted(bits uint64) bool {
+	for v := f.min; v <= f.max; v++ {
+		if bits&(1<<uint(v)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// value returns the value of a number or name in the field
+func (f cronField) value(expr string) (int, error) {
+	if v, ok := f.names[strings.ToLower(expr)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q in %v field", expr, f.name)
+	}
+	return v, nil
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron_Next(t *testing.T) {
+	from := time.Date(2026, time.March, 14, 10, 37, 20, 0, time.UTC) // a Saturday
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2026, time.March, 14, 10, 38, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, time.March, 14, 10, 45, 0, 0, time.UTC)},
+		{"5 9-17 * * *", time.Date(2026, time.March, 14, 11, 5, 0, 0, time.UTC)},
+		{"0 0 * * *", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * mon-fri", time.Date(2026, time.March, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2026, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 */1 * mon", time.Date(2026, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * */1", time.Date(2026, time.April, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 0-6", time.Date(2026, time.April, 13, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2026, time.March, 14, 10, 38, 50, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.spec, func(t *testing.T) {
+			cron, err := ParseCron(test.spec)
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.next, cron.Next(from))
+			}
+		})
+	}
+}
+
+func TestParseCron_NeverDue_ReturnsZeroTime(t *testing.T) {
+	cron, err := ParseCron("0 0 30 2 *")
+
+	assert.NoError(t, err)
+	assert.True(t, cron.Next(time.Now()).IsZero())
+}
+
+func TestParseCron_Invalid_ReturnsError(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "5-1 * * * *", "*/0 * * * *", "* * * foo *", "@every", "@every -1s"} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrDependencyCycle is returned when running a DAG whose work depends on itself
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// DAG is a graph of named work, where work is performed once the work it depends on has completed successfully
+type DAG struct {
+	nodes []*dagNode
+}
+
+type dagNode struct {
+	name      string
+	workToDo  ContextWork
+	dependsOn []string
+	options   []workOption
+}
+
+// NewDAG returns a reference to an initialized DAG
+func NewDAG() *DAG {
+	return &DAG{
+		nodes: []*dagNode{},
+	}
+}
+
+// Add adds work to the DAG with the name, performed once the work with the names in dependsOn has completed successfully.  The work is
+// enqueued with the name, which the options may override.
+func (d *DAG) Add(name string, workToDo ContextWork, dependsOn []string, options ...workOption) {
+	d.nodes = append(d.nodes, &dagNode{
+		name:      name,
+		workToDo:  workToDo,
+		dependsOn: dependsOn,
+		options:   options,
+	})
+}
+
+// RunDAG enqueues the work in the DAG and waits for it to finish, returning the finished work by name.  If any work did not complete
+// successfully the errors of the work are returned joined.  Work depending on work that did not complete successfully is cancelled with
+// ErrDependencyFailed.  Work coalesced into existing work with the same unique key is returned as the existing work.  If ctx is done before
+// the work has finished, the work enqueued so far is returned with ctx's error and the work continues on the queue, ctx only bounding how
+// long RunDAG waits.  If the DAG has a cycle ErrDependencyCycle is returned, and if work depends on a name not in the DAG
+// ErrUnknownDependency is returned, without any work being enqueued.
+func (w *Queue) RunDAG(ctx context.Context, dag *DAG) (map[string]*QueuedWork, error) {
+	order, err := dag.sort()
+	if err != nil {
+		return nil, err
+	}
+
+	wg := &sync.WaitGroup{}
+	mux := &sync.Mutex{}
+	errs := map[string]error{}
+	ids := map[string]uuid.UUID{}
+	work := map[string]*QueuedWork{}
+	for _, node := range order {
+		dependsOn := make([]uuid.UUID, 0, len(node.dependsOn))
+		for _, name := range node.dependsOn {
+			if id, ok := ids[name]; ok {
+				dependsOn = append(dependsOn, id)
+			}
+		}
+		name := node.name
+		// work depending on work that could not be enqueued is not enqueued either
+		if len(dependsOn) < len(node.dependsOn) {
+			mux.Lock()
+			errs[name] = fmt.Errorf("%w: dependency of %v not enqueued", ErrDependencyFailed, name)
+			mux.Unlock()
+			continue
+		}
+
+		var queued *workItem
+		options := append([]workOption{WithName(name)}, node.options...)
+		options = append(options, WithDependsOn(dependsOn...), func(item *workItem) {
+			queued = item
+			item.parentCtx = context.WithoutCancel(ctx)
+			item.onFinish = func(err error) {
+				defer wg.Done()
+				if err != nil {
+					mux.Lock()
+					defer mux.Unlock()
+					errs[name] = err
+				}
+			}
+		})
+
+		wg.Add(1)
+		id, err := w.EnqueueContext(ctx, node.workToDo, options...)
+		if err != nil {
+			wg.Done()
+			mux.Lock()
+			errs[name] = err
+			mux.Unlock()
+			if !errors.Is(err, ErrDependencyFailed) {
+				// the queue stopped or ctx is done, so remaining work cannot be enqueued
+				break
+			}
+			continue
+		}
+		ids[name] = id
+		work[name] = queued.QueuedWork
+		if queued.coalescedInto != nil {
+			work[name] = queued.coalescedInto
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return work, ctx.Err()
+	}
+
+	mux.Lock()
+	defer mux.Unlock()
+	joined := []error{}
+	for _, node := range order {
+		if err, ok := errs[node.name]; ok {
+			joined = append(joined, fmt.Errorf("%v: %w", node.name, err))
+		}
+	}
+	return work, errors.Join(joined...)
+}
+
+// sort returns the nodes of the DAG ordered so that work comes after the work it depends on
+func (d *DAG) sort() ([]*dagNode, error) {
+	index := map[string]*dagNode{}
+	for _, node := range d.nodes {
+		if _, ok := index[node.name]; ok {
+			return nil, fmt.Errorf("work %v added to DAG more than once", node.name)
+		}
+		index[node.name] = node
+	}
+
+	// Kahn's algorithm, taking ready work in the order it was added
+	remaining := map[string]int{}
+	dependents := map[string][]*dagNode{}
+	for _, node := range d.nodes {
+		for _, name := range node.dependsOn {
+			if _, ok := index[name]; !ok {
+				return nil, fmt.Errorf("%w: %v depends on %v", ErrUnknownDependency, node.name, name)
+			}
+			dependents[name] = append(dependents[name], node)
+		}
+		remaining[node.name] = len(node.dependsOn)
+	}
+
+	order := make([]*dagNode, 0, len(d.nodes))
+	for _, node := range d.nodes {
+		if remaining[node.name] == 0 {
+			order = append(order, node)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, dependent := range dependents[order[i].name] {
+			remaining[dependent.name]--
+			if remaining[dependent.name] == 0 {
+				order = append(order, dependent)
+			}
+		}
+	}
+
+	if len(order) < len(d.nodes) {
+		cycle := []string{}
+		for name, count := range remaining {
+			if count > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("%w: %v", ErrDependencyCycle, strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
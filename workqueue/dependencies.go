@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownDependency is returned when work depends on work that is neither queued nor in the queue's history
+var ErrUnknownDependency = errors.New("unknown dependency")
+
+// ErrDependencyFailed is the error of work cancelled because work it depends on did not complete successfully
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// holdForDependencies registers the work item as a dependent of the work it depends on that has not finished, returning an error if any of
+// the work it depends on is unknown or finished without completing successfully.  queueMux must be held.
+func (w *Queue) holdForDependencies(wi *workItem) error {
+	pending := []uuid.UUID{}
+	for _, id := range wi.dependsOn {
+		state := w.dependencyState(id)
+		switch {
+		case state < 0:
+			return fmt.Errorf("%w: %v", ErrUnknownDependency, id)
+		case state == COMPLETED:
+		case finalState(state):
+			return fmt.Errorf("%w: %v %v", ErrDependencyFailed, id, state)
+		default:
+			pending = append(pending, id)
+		}
+	}
+
+	for _, id := range pending {
+		w.dependents[id] = append(w.dependents[id], wi)
+	}
+	wi.waitingOn = len(pending)
+	return nil
+}
+
+// dependencyState returns the state of the queued or recently finished work with the id, or -1 if the work is unknown.  Work moves to the
+// history before it is removed from the queue's work items, so finished work is always found in one or the other.
+func (w *Queue) dependencyState(id uuid.UUID) workState {
+	if i, ok := w.workItems.Load(id); ok {
+		return workState(i.(*workItem).state.Load())
+	}
+	if work, ok := w.history.Find(id); ok {
+		return workState(work.state.Load())
+	}
+	return -1
+}
+
+// releaseDependents queues the work waiting on the finished work item once all of its dependencies have completed, or returns the waiting work
+// to be cancelled if the work item did not complete successfully.  Work released once the queue has stopped is returned to be skipped.
+// queueMux must be held.
+func (w *Queue) releaseDependents(wi *workItem, state workState) (cancelled []*workItem, skipped []*workItem) {
+	dependents := w.dependents[wi.id]
+	delete(w.dependents, wi.id)
+
+	for _, dependent := range dependents {
+		switch {
+		case state != COMPLETED:
+			if dependent.state.CompareAndSwap(int32(WAITING), int32(CANCELLED)) {
+				cancelled = append(cancelled, dependent)
+			}
+		case dependent.state.Load() == int32(WAITING):
+			dependent.waitingOn--
+			if dependent.waitingOn > 0 {
+				continue
+			}
+			if w.queueContext.Err() != nil {
+				if dependent.state.CompareAndSwap(int32(WAITING), int32(SKIPPED)) {
+					skipped = append(skipped, dependent)
+				}
+			} else if dependent.state.CompareAndSwap(int32(WAITING), int32(IN_QUEUE)) {
+				w.pushOrSchedule(dependent)
+			}
+		}
+	}
+	return cancelled, skipped
+}
+
+// finalState returns true if work in the state has finished
+func finalState(state workState) bool {
+	return state == COMPLETED || state == FAILED || state == CANCELLED || state == SKIPPED
+}
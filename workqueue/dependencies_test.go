@@ -0,0 +1,267 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_WithDependsOn_WaitsForDependencies(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(4))
+	defer q.Stop()
+	mux := &sync.Mutex{}
+	order := []string{}
+	record := func(name string) ContextWork {
+		return func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 10)
+			mux.Lock()
+			defer mux.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	a, _ := q.EnqueueContext(context.Background(), record("a"))
+	c, _ := q.EnqueueContext(context.Background(), record("c"))
+
+	// test
+	b := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		return true, record("b")(ctx)
+	}, WithDependsOn(a, c))
+	work, _ := q.FindWork(b.Id())
+	state := work.State()
+
+	// assert
+	assert.Equal(t, WAITING.String(), state)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := b.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, order, 3)
+	assert.Equal(t, "b", order[2])
+}
+
+func TestQueue_WithDependsOn_FailurePropagates(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	a, _ := q.Enqueue(func() error {
+		<-release
+		return errors.New("failed")
+	})
+	b, _ := q.Enqueue(func() error { return nil }, WithDependsOn(a))
+	c := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		return true, nil
+	}, WithDependsOn(b))
+
+	// test
+	close(release)
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := c.Wait(ctx)
+	assert.ErrorIs(t, err, ErrDependencyFailed)
+	work, _ := q.FindWork(b)
+	assert.Equal(t, CANCELLED.String(), work.State())
+	assert.ErrorIs(t, work.Err(), ErrDependencyFailed)
+}
+
+func TestQueue_WithDependsOn_FinishedDependencies(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	completed := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) { return true, nil })
+	failed := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) { return false, errors.New("failed") })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, _ = completed.Wait(ctx)
+	_, _ = failed.Wait(ctx)
+
+	// test
+	_, errCompleted := q.Enqueue(func() error { return nil }, WithDependsOn(completed.Id()))
+	_, errFailed := q.Enqueue(func() error { return nil }, WithDependsOn(failed.Id()))
+	_, errUnknown := q.Enqueue(func() error { return nil }, WithDependsOn(uuid.New()))
+
+	// assert
+	assert.NoError(t, errCompleted)
+	assert.ErrorIs(t, errFailed, ErrDependencyFailed)
+	assert.ErrorIs(t, errUnknown, ErrUnknownDependency)
+}
+
+func TestQueue_Dequeue_WaitingWork_CancelsDependents(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	a, _ := q.Enqueue(func() error {
+		<-release
+		return nil
+	})
+	b, _ := q.Enqueue(func() error { return nil }, WithDependsOn(a))
+	c, _ := q.Enqueue(func() error { return nil }, WithDependsOn(b))
+
+	// test
+	err := q.Dequeue(b)
+	close(release)
+
+	// assert
+	assert.NoError(t, err)
+	work, _ := q.FindWork(c)
+	assert.Equal(t, CANCELLED.String(), work.State())
+}
+
+func TestQueue_RunDAG_PerformsWorkInDependencyOrder(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(4))
+	defer q.Stop()
+	mux := &sync.Mutex{}
+	finished := map[string]time.Time{}
+	record := func(name string) ContextWork {
+		return func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 5)
+			mux.Lock()
+			defer mux.Unlock()
+			finished[name] = time.Now()
+			return nil
+		}
+	}
+	dag := NewDAG()
+	dag.Add("build", record("build"), []string{"fetch", "configure"})
+	dag.Add("fetch", record("fetch"), nil)
+	dag.Add("configure", record("configure"), nil)
+	dag.Add("test", record("test"), []string{"build"}, WithPriority(2))
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	work, err := q.RunDAG(ctx, dag)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, work, 4)
+	assert.Equal(t, COMPLETED.String(), work["test"].State())
+	assert.Equal(t, "test", work["test"].Name())
+	assert.True(t, finished["build"].After(finished["fetch"]))
+	assert.True(t, finished["build"].After(finished["configure"]))
+	assert.True(t, finished["test"].After(finished["build"]))
+}
+
+func TestQueue_RunDAG_Failure_CancelsDependents(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2))
+	defer q.Stop()
+	errTest := errors.New("test")
+	dag := NewDAG()
+	dag.Add("a", func(ctx context.Context) error { return errTest }, nil)
+	dag.Add("b", func(ctx context.Context) error { return nil }, []string{"a"})
+	dag.Add("c", func(ctx context.Context) error { return nil }, nil)
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	work, err := q.RunDAG(ctx, dag)
+
+	// assert
+	assert.ErrorIs(t, err, errTest)
+	assert.ErrorIs(t, err, ErrDependencyFailed)
+	assert.Equal(t, COMPLETED.String(), work["c"].State())
+	if b, ok := work["b"]; ok {
+		assert.Equal(t, CANCELLED.String(), b.State())
+	}
+}
+
+func TestQueue_RunDAG_ContextDone_WorkContinues(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	dag := NewDAG()
+	dag.Add("a", func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}, nil)
+	dag.Add("b", func(ctx context.Context) error { return ctx.Err() }, []string{"a"})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+
+	// test
+	work, err := q.RunDAG(ctx, dag)
+	close(release)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Eventually(t, func() bool {
+		return work["b"].State() == COMPLETED.String()
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, COMPLETED.String(), work["a"].State())
+}
+
+func TestQueue_RunDAG_CoalescedWork_ReturnsExistingWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		<-release
+		return nil
+	})
+	existing, _ := q.Enqueue(func() error { return nil }, WithUniqueKey("report"))
+	dag := NewDAG()
+	dag.Add("report", func(ctx context.Context) error { return nil }, nil, WithUniqueKey("report"))
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		close(release)
+	}()
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	work, err := q.RunDAG(ctx, dag)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, existing.String(), work["report"].Id())
+	assert.Equal(t, COMPLETED.String(), work["report"].State())
+}
+
+func TestQueue_RunDAG_Cycle_ReturnsError(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	dag := NewDAG()
+	dag.Add("a", func(ctx context.Context) error { return nil }, []string{"c"})
+	dag.Add("b", func(ctx context.Context) error { return nil }, []string{"a"})
+	dag.Add("c", func(ctx context.Context) error { return nil }, []string{"b"})
+	dag.Add("d", func(ctx context.Context) error { return nil }, nil)
+
+	// test
+	_, err := q.RunDAG(context.Background(), dag)
+	_, errUnknown := q.RunDAG(context.Background(), func() *DAG {
+		d := NewDAG()
+		d.Add("a", func(ctx context.Context) error { return nil }, []string{"missing"})
+		return d
+	}())
+
+	// assert
+	assert.ErrorIs(t, err, ErrDependencyCycle)
+	assert.EqualError(t, err, "dependency cycle: a, b, c")
+	assert.ErrorIs(t, errUnknown, ErrUnknownDependency)
+	assert.Empty(t, q.WorkItems())
+}
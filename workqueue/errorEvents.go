@@ -0,0 +1,155 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// defaultErrorBuffer is the number of errors buffered for subscribers returned by Errors
+const defaultErrorBuffer = 100
+
+// ErrorEvent describes work that failed.  The event is itself an error wrapping the error of the work.
+type ErrorEvent struct {
+	Id       uuid.UUID
+	Name     string
+	Priority int
+	Attempt  int
+	Duration time.Duration
+	Err      error
+}
+
+func newErrorEvent(wi *workItem, err error) *ErrorEvent {
+	return &ErrorEvent{
+		Id:       wi.id,
+		Name:     wi.name,
+		Priority: wi.Priority(),
+		Attempt:  wi.Attempt(),
+		Duration: wi.RunTime(),
+		Err:      err,
+	}
+}
+
+// Error returns the error of the work, identifying the work that failed
+func (e *ErrorEvent) Error() string {
+	if e.Name != "" {
+		return fmt.Sprintf("work %v (%v) failed on attempt %v: %v", e.Name, e.Id, e.Attempt, e.Err)
+	}
+	return fmt.Sprintf("work %v failed on attempt %v: %v", e.Id, e.Attempt, e.Err)
+}
+
+// Unwrap returns the error of the work
+func (e *ErrorEvent) Unwrap() error {
+	return e.Err
+}
+
+// ErrorSubscription receives events for work that failed until it is unsubscribed or the queue has stopped and all work has finished
+type ErrorSubscription struct {
+	id      uint64
+	events  chan *ErrorEvent
+	dropped atomic.Int64
+	queue   *Queue
+}
+
+// errorSubscriber delivers error events to a subscriber without blocking
+type errorSubscriber struct {
+	deliver func(e *ErrorEvent) bool
+	close   func()
+	dropped *atomic.Int64
+}
+
+// SubscribeErrors subscribes to events for work that failed.  Events are delivered without blocking the queue, so events arriving while
+// the subscription's buffer is full are dropped and counted.  The subscription's channel is closed once the subscription is unsubscribed
+// or the queue has stopped and all work has finished.
+func (w *Queue) SubscribeErrors(buffer int) *ErrorSubscription {
+	sub := &ErrorSubscription{
+		events: make(chan *ErrorEvent, max(buffer, 0)),
+		queue:  w,
+	}
+	sub.id = w.subscribeErrors(&errorSubscriber{
+		deliver: func(e *ErrorEvent) bool {
+			select {
+			case sub.events <- e:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() {
+			close(sub.events)
+		},
+		dropped: &sub.dropped,
+	})
+	return sub
+}
+
+// Events returns the channel events are delivered on
+func (s *ErrorSubscription) Events() <-chan *ErrorEvent {
+	return s.events
+}
+
+// Dropped returns the number of events dropped because the subscription's buffer was full
+func (s *ErrorSubscription) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe stops events being delivered to the subscription and closes its channel
+func (s *ErrorSubscription) Unsubscribe() {
+	s.queue.unsubscribeErrors(s.id)
+}
+
+// subscribeErrors adds the subscriber, closing it straight away if the queue's errors have been closed, returning its id
+func (w *Queue) subscribeErrors(sub *errorSubscriber) uint64 {
+	w.errSubScriberMux.Lock()
+	defer w.errSubScriberMux.Unlock()
+
+	if w.errorsClosed {
+		sub.close()
+		return 0
+	}
+	w.errSubscriberSeq++
+	w.errorSubscribers[w.errSubscriberSeq] = sub
+	return w.errSubscriberSeq
+}
+
+// unsubscribeErrors removes the subscriber with the id, closing it
+func (w *Queue) unsubscribeErrors(id uint64) {
+	w.errSubScriberMux.Lock()
+	defer w.errSubScriberMux.Unlock()
+
+	if sub, ok := w.errorSubscribers[id]; ok {
+		delete(w.errorSubscribers, id)
+		sub.close()
+	}
+}
+
+// publishErrors publishes error events to error subscribers, closing the subscribers once all work has finished.  Subscribers are
+// delivered to under the lock so they are not closed during delivery, which never blocks.
+func (w *Queue) publishErrors(published chan struct{}) {
+	defer close(published)
+	for e := range w.errChan {
+		w.errSubScriberMux.Lock()
+		for _, sub := range w.errorSubscribers {
+			if !sub.deliver(e) {
+				sub.dropped.Add(1)
+			}
+		}
+		w.errSubScriberMux.Unlock()
+	}
+
+	w.errSubScriberMux.Lock()
+	defer w.errSubScriberMux.Unlock()
+	for _, sub := range w.errorSubscribers {
+		sub.close()
+	}
+	w.errorSubscribers = map[uint64]*errorSubscriber{}
+	w.errorsClosed = true
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_SubscribeErrors_ReceivesEventWithWorkDetails(t *testing.T) {
+	// setup
+	errTest := errors.New("test")
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	sub := q.SubscribeErrors(1)
+	defer sub.Unsubscribe()
+
+	// test
+	id, _ := q.Enqueue(func() error {
+		time.Sleep(time.Millisecond * 10)
+		return errTest
+	}, WithName("failing"), WithPriority(3))
+
+	// assert
+	select {
+	case e := <-sub.Events():
+		assert.Equal(t, id, e.Id)
+		assert.Equal(t, "failing", e.Name)
+		assert.Equal(t, 3, e.Priority)
+		assert.Equal(t, 1, e.Attempt)
+		assert.GreaterOrEqual(t, e.Duration, time.Millisecond*10)
+		assert.ErrorIs(t, e, errTest)
+		assert.Equal(t, "work failing ("+id.String()+") failed on attempt 1: test", e.Error())
+	case <-time.After(time.Second):
+		assert.Fail(t, "error event not received")
+	}
+}
+
+func TestQueue_Errors_ReportsErrorEvent(t *testing.T) {
+	// setup
+	errTest := errors.New("test")
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	errCh := q.Errors()
+
+	// test
+	id, _ := q.Enqueue(func() error {
+		return errTest
+	})
+
+	// assert
+	select {
+	case err := <-errCh:
+		var e *ErrorEvent
+		assert.ErrorAs(t, err, &e)
+		assert.Equal(t, id, e.Id)
+		assert.ErrorIs(t, err, errTest)
+	case <-time.After(time.Second):
+		assert.Fail(t, "error not received")
+	}
+}
+
+func TestQueue_SubscribeErrors_SlowSubscriber_DoesNotBlockWorkers(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	sub := q.SubscribeErrors(0)
+	defer sub.Unsubscribe()
+
+	// test
+	for i := 0; i < 5; i++ {
+		_, _ = q.Enqueue(func() error {
+			return errors.New("test")
+		})
+	}
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		return true, nil
+	})
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.True(t, done)
+	assert.Eventually(t, func() bool {
+		return sub.Dropped() == 5
+	}, time.Second, time.Millisecond)
+}
+
+func TestErrorSubscription_Unsubscribe_ClosesChannel(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	sub := q.SubscribeErrors(1)
+
+	// test
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+
+	// assert
+	_, ok := <-sub.Events()
+	assert.False(t, ok, "subscription channel should be closed")
+}
+
+func TestQueue_SubscribeErrors_AfterShutdown_ReturnsClosedChannel(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// test
+	sub := q.SubscribeErrors(1)
+
+	// assert
+	_, ok := <-sub.Events()
+	assert.False(t, ok, "subscription channel should be closed")
+}
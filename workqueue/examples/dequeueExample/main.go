@@ -42,18 +42,24 @@ func main() {
 
 	for i, w := range work {
 		wg.Add(1)
-		q.Enqueue(w.work, workqueue.WithPriority(w.priority), workqueue.WithName(fmt.Sprintf("Work Task %v", i)))
+		if _, err := q.Enqueue(w.work, workqueue.WithPriority(w.priority), workqueue.WithName(fmt.Sprintf("Work Task %v", i))); err != nil {
+			fmt.Println(err)
+			wg.Done()
+		}
 	}
 
 	fmt.Println("Before Adding Temporary Work")
 	printItems(q)
 
 	// Queue new task
-	id := q.Enqueue(func() error {
+	id, err := q.Enqueue(func() error {
 		time.Sleep(time.Second)
 		fmt.Println("Done with prioritized work!")
 		return nil
 	}, workqueue.WithPriority(99), workqueue.WithName("Temporary"))
+	if err != nil {
+		fmt.Println(err)
+	}
 
 	fmt.Println("After Queing Temprary Work")
 	printItems(q)
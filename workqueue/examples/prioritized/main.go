@@ -44,7 +44,10 @@ func main() {
 
 	for _, w := range work {
 		wg.Add(1)
-		q.Enqueue(w.work, workqueue.WithPriority(w.priority))
+		if _, err := q.Enqueue(w.work, workqueue.WithPriority(w.priority)); err != nil {
+			fmt.Println(err)
+			wg.Done()
+		}
 	}
 
 	wg.Wait()
@@ -43,7 +43,10 @@ func main() {
 
 	for i, w := range work {
 		wg.Add(1)
-		q.Enqueue(w.work, workqueue.WithPriority(w.priority), workqueue.WithName(fmt.Sprintf("Work Task %v", i)))
+		if _, err := q.Enqueue(w.work, workqueue.WithPriority(w.priority), workqueue.WithName(fmt.Sprintf("Work Task %v", i))); err != nil {
+			fmt.Println(err)
+			wg.Done()
+		}
 	}
 
 	fmt.Println("Before Adding Prioritized Work")
@@ -63,7 +66,7 @@ func main() {
 
 	// Queue prioritized task
 	wg.Add(1)
-	q.Enqueue(func() error {
+	_, err := q.Enqueue(func() error {
 		defer wg.Done()
 		time.Sleep(time.Second)
 		fmt.Println("Done with prioritized work!")
@@ -71,6 +74,10 @@ func main() {
 	}, workqueue.WithPriority(99), workqueue.WithName("Prioritized"), workqueue.WithAdjustPriority(func() int {
 		return int(priority.Load())
 	}))
+	if err != nil {
+		fmt.Println(err)
+		wg.Done()
+	}
 
 	fmt.Println("After Queing Prioritized Work")
 	printItems(q)
@@ -39,7 +39,10 @@ func main() {
 	// Queue the work
 	for _, w := range work {
 		wg.Add(1)
-		q.Enqueue(w)
+		if _, err := q.Enqueue(w); err != nil {
+			fmt.Println(err)
+			wg.Done()
+		}
 	}
 
 	wg.Wait()
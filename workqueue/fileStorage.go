@@ -0,0 +1,286 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// defaultCompactThreshold is the number of removed work records a FileStorage's log accumulates before it is compacted by default
+const defaultCompactThreshold = 1000
+
+// corruptSuffix is appended to the path of a log holding records that could not be decoded, to name the copy of the log kept for recovery
+const corruptSuffix = ".corrupt"
+
+// walRecord is an entry in a FileStorage's write-ahead log
+type walRecord struct {
+	Op   string      `json:"op"`
+	Work *StoredWork `json:"work,omitempty"`
+	Id   uuid.UUID   `json:"id,omitempty"`
+}
+
+const (
+	walSave   = "save"
+	walRemove = "remove"
+)
+
+type FileStorageOption func(storage *FileStorage)
+
+// FileStorage is Storage keeping work in a write-ahead log file of JSON records.  Each save and remove is appended to the log and synced to
+// disk before returning.  The log is compacted when opened, and once the number of removed records exceeds the compact threshold.
+type FileStorage struct {
+	path             string
+	mux              *sync.Mutex
+	file             *os.File
+	work             map[uuid.UUID]*StoredWork
+	order            []uuid.UUID
+	removed          int
+	corrupted        int
+	compactThreshold int
+}
+
+// NewFileStorage opens, or creates, the write-ahead log at path returning a reference to an initialized FileStorage.  A partially written
+// record at the end of the log, left by a crash while writing, is discarded.  Other records that cannot be decoded are skipped, and the log
+// is copied to path with the suffix .corrupt before it is compacted so the skipped records are not lost.
+func NewFileStorage(path string, options ...FileStorageOption) (*FileStorage, error) {
+	s := &FileStorage{
+		path:             path,
+		mux:              &sync.Mutex{},
+		work:             map[uuid.UUID]*StoredWork{},
+		order:            []uuid.UUID{},
+		compactThreshold: defaultCompactThreshold,
+	}
+	for _, o := range options {
+		o(s)
+	}
+
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	if s.corrupted > 0 {
+		if err := s.preserve(); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// WithCompactThreshold sets the number of removed work records the log accumulates before it is compacted
+func WithCompactThreshold(threshold int) FileStorageOption {
+	return func(storage *FileStorage) {
+		storage.compactThreshold = threshold
+	}
+}
+
+// Save appends the work to the log
+func (s *FileStorage) Save(work *StoredWork) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.append(walRecord{Op: walSave, Work: work}); err != nil {
+		return err
+	}
+	if _, ok := s.work[work.Id]; !ok {
+		s.order = append(s.order, work.Id)
+	}
+	s.work[work.Id] = work
+	return nil
+}
+
+// Remove appends the removal of the work with the id to the log, compacting the log once the compact threshold is exceeded
+func (s *FileStorage) Remove(id uuid.UUID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if _, ok := s.work[id]; !ok {
+		return nil
+	}
+	if err := s.append(walRecord{Op: walRemove, Id: id}); err != nil {
+		return err
+	}
+	delete(s.work, id)
+	s.removed++
+	if s.removed > s.compactThreshold {
+		return s.compact()
+	}
+	return nil
+}
+
+// Load returns the work saved and not removed, in the order it was saved
+func (s *FileStorage) Load() ([]*StoredWork, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	result := make([]*StoredWork, 0, len(s.work))
+	for _, id := range s.order {
+		if work, ok := s.work[id]; ok {
+			result = append(result, work)
+		}
+	}
+	return result, nil
+}
+
+// Corrupted returns the number of records that could not be decoded when the log was opened, other than a partially written last record.
+// The log holding them is kept at the storage's path with the suffix .corrupt.
+func (s *FileStorage) Corrupted() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.corrupted
+}
+
+// Close closes the log file
+func (s *FileStorage) Close() error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.file.Close()
+}
+
+// read replays the records in the log file, skipping records that cannot be decoded.  Only the last record may have been partially written,
+// so any other record that cannot be decoded is counted as corrupted.
+func (s *FileStorage) read() error {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	undecoded := false
+	for scanner.Scan() {
+		if undecoded {
+			// the record that could not be decoded was not the last record in the log
+			s.corrupted++
+		}
+		record := walRecord{}
+		if undecoded = json.Unmarshal(scanner.Bytes(), &record) != nil; undecoded {
+			continue
+		}
+		switch record.Op {
+		case walSave:
+			if record.Work == nil {
+				continue
+			}
+			if _, ok := s.work[record.Work.Id]; !ok {
+				s.order = append(s.order, record.Work.Id)
+			}
+			s.work[record.Work.Id] = record.Work
+		case walRemove:
+			delete(s.work, record.Id)
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the log with only the work that has not been removed, replacing the log file once the new log is written.  mux must be
+// held once the storage has been initialized.
+func (s *FileStorage) compact() error {
+	order := make([]uuid.UUID, 0, len(s.work))
+	for _, id := range s.order {
+		if _, ok := s.work[id]; ok {
+			order = append(order, id)
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".wal-*")
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	encoder := json.NewEncoder(writer)
+	for _, id := range order {
+		if err = encoder.Encode(walRecord{Op: walSave, Work: s.work[id]}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	// the rename is only durable once the directory is synced
+	if err = syncDir(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+
+	if s.file != nil {
+		_ = s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	s.order = order
+	s.removed = 0
+	return nil
+}
+
+// preserve copies the log to its path with the corrupt suffix, syncing the copy to disk
+func (s *FileStorage) preserve() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path+corruptSuffix, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// syncDir syncs the directory to disk, making files created in or renamed into it durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// append appends the record to the log, syncing it to disk.  mux must be held.
+func (s *FileStorage) append(record walRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err = s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorage_Reopen_LoadsSavedWork(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path)
+	assert.NoError(t, err)
+	work1 := &StoredWork{Id: uuid.New(), Handler: "handler", Payload: []byte(`{"value":1}`)}
+	work2 := &StoredWork{Id: uuid.New(), Handler: "handler", Payload: []byte(`{"value":2}`)}
+	work3 := &StoredWork{Id: uuid.New(), Handler: "handler", Payload: []byte(`{"value":3}`)}
+	assert.NoError(t, storage.Save(work1))
+	assert.NoError(t, storage.Save(work2))
+	assert.NoError(t, storage.Save(work3))
+	assert.NoError(t, storage.Remove(work2.Id))
+	assert.NoError(t, storage.Close())
+
+	// test
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	loaded, err := storage.Load()
+
+	// assert
+	assert.NoError(t, err)
+	if assert.Len(t, loaded, 2) {
+		assert.Equal(t, work1.Id, loaded[0].Id)
+		assert.JSONEq(t, `{"value":1}`, string(loaded[0].Payload))
+		assert.Equal(t, work3.Id, loaded[1].Id)
+	}
+}
+
+func TestFileStorage_PartialRecord_IsDiscarded(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path)
+	assert.NoError(t, err)
+	work := &StoredWork{Id: uuid.New(), Handler: "handler"}
+	assert.NoError(t, storage.Save(work))
+	assert.NoError(t, storage.Close())
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = f.WriteString(`{"op":"save","work":{"id":`)
+	assert.NoError(t, f.Close())
+
+	// test
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	loaded, _ := storage.Load()
+
+	// assert
+	assert.Len(t, loaded, 1)
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+}
+
+func TestFileStorage_CorruptRecord_IsSkippedAndLogKept(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path)
+	assert.NoError(t, err)
+	work1 := &StoredWork{Id: uuid.New(), Handler: "handler"}
+	assert.NoError(t, storage.Save(work1))
+	assert.NoError(t, storage.Close())
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = f.WriteString(`{"op":"save","work":{"id":` + "\n")
+	assert.NoError(t, f.Close())
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	work2 := &StoredWork{Id: uuid.New(), Handler: "handler"}
+	assert.NoError(t, storage.Save(work2))
+	assert.NoError(t, storage.Close())
+	original, _ := os.ReadFile(path)
+	f, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, _ = f.WriteString("corrupt\n")
+	work3 := &StoredWork{Id: uuid.New(), Handler: "handler"}
+	data, _ := json.Marshal(walRecord{Op: walSave, Work: work3})
+	_, _ = f.Write(append(data, '\n'))
+	assert.NoError(t, f.Close())
+	corrupt, _ := os.ReadFile(path)
+
+	// test
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	loaded, _ := storage.Load()
+
+	// assert
+	assert.Equal(t, 1, storage.Corrupted())
+	if assert.Len(t, loaded, 3) {
+		assert.Equal(t, work1.Id, loaded[0].Id)
+		assert.Equal(t, work2.Id, loaded[1].Id)
+		assert.Equal(t, work3.Id, loaded[2].Id)
+	}
+	assert.Equal(t, 2, strings.Count(string(original), "\n"), "Expected the partially written record to be discarded")
+	kept, err := os.ReadFile(path + corruptSuffix)
+	assert.NoError(t, err)
+	assert.Equal(t, string(corrupt), string(kept))
+}
+
+func TestFileStorage_Remove_CompactsLog(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path, WithCompactThreshold(2))
+	assert.NoError(t, err)
+	defer storage.Close()
+	kept := &StoredWork{Id: uuid.New(), Handler: "handler"}
+	assert.NoError(t, storage.Save(kept))
+
+	// test
+	for i := 0; i < 3; i++ {
+		work := &StoredWork{Id: uuid.New(), Handler: "handler"}
+		assert.NoError(t, storage.Save(work))
+		assert.NoError(t, storage.Remove(work.Id))
+	}
+
+	// assert
+	data, _ := os.ReadFile(path)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"))
+	assert.Contains(t, string(data), kept.Id.String())
+}
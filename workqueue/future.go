@@ -0,0 +1,113 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotFinished is returned by Future.Result when the work has not finished
+var ErrNotFinished = errors.New("work has not finished")
+
+// Future provides the result of work enqueued with EnqueueFunc once the work has finished
+type Future[T any] struct {
+	id    uuid.UUID
+	done  chan struct{}
+	mux   *sync.Mutex
+	value T
+	err   error
+}
+
+// EnqueueFunc queues work returning a value on the queue, returning a Future providing the value (or error) once the work has finished.
+// If the work is retried, the future is resolved by the final attempt.  If the work is dequeued the future is resolved with context.Canceled.
+// If the work could not be enqueued, the future is resolved with the error returned when enqueuing the work.  If the work is coalesced into
+// existing work with the same unique key, the future is resolved with the error of the existing work, without a value.
+func EnqueueFunc[T any](ctx context.Context, q *Queue, fn func(ctx context.Context) (T, error), options ...workOption) *Future[T] {
+	f := &Future[T]{
+		done: make(chan struct{}),
+		mux:  &sync.Mutex{},
+	}
+
+	work := func(ctx context.Context) error {
+		value, err := fn(ctx)
+		if err == nil {
+			f.setValue(value)
+		}
+		return err
+	}
+	options = append(options, func(item *workItem) {
+		item.onFinish = f.resolve
+	})
+	id, err := q.EnqueueContext(ctx, work, options...)
+	if err != nil {
+		f.resolve(err)
+	}
+	f.id = id
+
+	return f
+}
+
+// Id returns the id of the work the future is the result of
+func (f *Future[T]) Id() uuid.UUID {
+	return f.id
+}
+
+// Done returns a channel that is closed when the work has finished
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result returns the value and error returned by the work.  If the work has not finished, the zero value of T and ErrNotFinished is returned.
+func (f *Future[T]) Result() (T, error) {
+	select {
+	case <-f.done:
+		f.mux.Lock()
+		defer f.mux.Unlock()
+		return f.value, f.err
+	default:
+		var zero T
+		return zero, ErrNotFinished
+	}
+}
+
+// Wait blocks until the work has finished, returning the value and error returned by the work, or until ctx is done, returning ctx's error
+func (f *Future[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.Result()
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+func (f *Future[T]) setValue(value T) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	select {
+	case <-f.done:
+		// the future has already been resolved
+	default:
+		f.value = value
+	}
+}
+
+// resolve resolves the future with err, ignoring all but the first resolution
+func (f *Future[T]) resolve(err error) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	select {
+	case <-f.done:
+	default:
+		f.err = err
+		close(f.done)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueueFunc_Wait_ReturnsValue(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+
+	// test
+	futures := make([]*Future[int], 10)
+	for i := range futures {
+		value := i
+		futures[i] = EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+			return value * 2, nil
+		})
+	}
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i, f := range futures {
+		value, err := f.Wait(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, i*2, value)
+	}
+}
+
+func TestEnqueueFunc_Wait_ReturnsError(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	errTest := errors.New("test")
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (string, error) {
+		return "ignored", errTest
+	})
+
+	// assert
+	<-f.Done()
+	value, err := f.Result()
+	assert.Equal(t, errTest, err)
+	assert.Equal(t, "", value)
+}
+
+func TestEnqueueFunc_WithRetryPolicy_ResolvedByFinalAttempt(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	attempts := atomic.Int32{}
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int32, error) {
+		attempt := attempts.Add(1)
+		if attempt < 3 {
+			return 0, errors.New("failed")
+		}
+		return attempt, nil
+	}, WithRetryPolicy(NewRetryPolicy(3)))
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	value, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), value)
+}
+
+func TestFuture_Result_NotFinished_ReturnsErrNotFinished(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	_, err := f.Result()
+	close(release)
+
+	// assert
+	assert.ErrorIs(t, err, ErrNotFinished)
+	<-f.Done()
+}
+
+func TestFuture_Wait_ContextDone_ReturnsContextError(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	defer close(release)
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+		<-release
+		return 1, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	// test
+	_, err := f.Wait(ctx)
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,90 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// InState returns a filter for History selecting work in any of the states
+func InState(states ...workState) func(*QueuedWork) bool {
+	return func(work *QueuedWork) bool {
+		state := workState(work.state.Load())
+		for _, s := range states {
+			if s == state {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// workHistory retains the most recently finished work up to a maximum size, discarding the oldest work when full
+type workHistory struct {
+	items []*QueuedWork
+	next  int
+	full  bool
+	mux   *sync.RWMutex
+}
+
+func newWorkHistory(size int) *workHistory {
+	return &workHistory{
+		items: make([]*QueuedWork, max(size, 0)),
+		mux:   &sync.RWMutex{},
+	}
+}
+
+// Add adds finished work to the history
+func (h *workHistory) Add(work *QueuedWork) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if len(h.items) == 0 {
+		return
+	}
+	h.items[h.next] = work
+	h.next = (h.next + 1) % len(h.items)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Items returns the work in the history ordered from oldest to most recently finished, filtered to the work the filters return true for
+func (h *workHistory) Items(filters ...func(*QueuedWork) bool) []*QueuedWork {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	ordered := h.items[:h.next]
+	if h.full {
+		ordered = append(append([]*QueuedWork{}, h.items[h.next:]...), ordered...)
+	}
+
+	result := []*QueuedWork{}
+outsideFor:
+	for _, work := range ordered {
+		for _, filter := range filters {
+			if !filter(work) {
+				continue outsideFor
+			}
+		}
+		result = append(result, work)
+	}
+	return result
+}
+
+// Find returns the work in the history with the id
+func (h *workHistory) Find(id uuid.UUID) (*QueuedWork, bool) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	for _, work := range h.items {
+		if work != nil && work.id == id {
+			return work, true
+		}
+	}
+	return nil, false
+}
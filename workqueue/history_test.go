@@ -0,0 +1,189 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func newFinishedWork(name string, state workState) *QueuedWork {
+	work := &QueuedWork{
+		id:    uuid.New(),
+		name:  name,
+		state: &atomic.Int32{},
+	}
+	work.state.Store(int32(state))
+	return work
+}
+
+func TestWorkHistory_Items_ReturnsOldestToNewest(t *testing.T) {
+	// setup
+	h := newWorkHistory(3)
+
+	// test
+	h.Add(newFinishedWork("work1", COMPLETED))
+	h.Add(newFinishedWork("work2", COMPLETED))
+
+	// assert
+	items := h.Items()
+	assert.Len(t, items, 2)
+	assert.Equal(t, "work1", items[0].Name())
+	assert.Equal(t, "work2", items[1].Name())
+}
+
+func TestWorkHistory_Add_Full_DiscardsOldest(t *testing.T) {
+	// setup
+	h := newWorkHistory(3)
+
+	// test
+	for _, name := range []string{"work1", "work2", "work3", "work4", "work5"} {
+		h.Add(newFinishedWork(name, COMPLETED))
+	}
+
+	// assert
+	items := h.Items()
+	assert.Len(t, items, 3)
+	assert.Equal(t, "work3", items[0].Name())
+	assert.Equal(t, "work4", items[1].Name())
+	assert.Equal(t, "work5", items[2].Name())
+}
+
+func TestWorkHistory_Items_Filtered(t *testing.T) {
+	// setup
+	h := newWorkHistory(5)
+	h.Add(newFinishedWork("work1", COMPLETED))
+	h.Add(newFinishedWork("work2", FAILED))
+	h.Add(newFinishedWork("work3", CANCELLED))
+
+	// test
+	items := h.Items(InState(FAILED, CANCELLED))
+
+	// assert
+	assert.Len(t, items, 2)
+	assert.Equal(t, "work2", items[0].Name())
+	assert.Equal(t, "work3", items[1].Name())
+}
+
+func TestWorkHistory_ZeroSize_RetainsNothing(t *testing.T) {
+	// setup
+	h := newWorkHistory(0)
+
+	// test
+	h.Add(newFinishedWork("work1", COMPLETED))
+
+	// assert
+	assert.Empty(t, h.Items())
+}
+
+func TestWorkHistory_Find(t *testing.T) {
+	// setup
+	h := newWorkHistory(2)
+	work := newFinishedWork("work1", COMPLETED)
+	h.Add(work)
+
+	// test
+	found, ok := h.Find(work.id)
+	_, notOk := h.Find(uuid.New())
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, work, found)
+	assert.False(t, notOk)
+}
+
+func TestQueue_History_RecordsFinishedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	errTest := errors.New("test")
+
+	// test
+	completed := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+		time.Sleep(time.Millisecond)
+		return 1, nil
+	}, WithName("completed"))
+	failed := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+		return 0, errTest
+	}, WithName("failed"))
+	<-completed.Done()
+	<-failed.Done()
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return len(q.History()) == 2
+	}, time.Second, time.Millisecond)
+	work, ok := q.FindWork(completed.Id())
+	assert.True(t, ok)
+	assert.Equal(t, COMPLETED.String(), work.State())
+	assert.NoError(t, work.Err())
+	assert.False(t, work.EnqueuedAt().IsZero())
+	assert.False(t, work.StartedAt().Before(work.EnqueuedAt()))
+	assert.False(t, work.FinishedAt().Before(work.StartedAt()))
+	assert.GreaterOrEqual(t, work.RunTime(), time.Millisecond)
+
+	failedHistory := q.History(InState(FAILED))
+	assert.Len(t, failedHistory, 1)
+	assert.Equal(t, "failed", failedHistory[0].Name())
+	assert.Equal(t, errTest, failedHistory[0].Err())
+	assert.Empty(t, q.WorkItems())
+}
+
+func TestQueue_Dequeue_RecordsCancelledWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	defer close(release)
+	q.Enqueue(func() error {
+		<-release
+		return nil
+	})
+	// the only worker is busy so the following work stays queued
+	id, _ := q.Enqueue(func() error {
+		return nil
+	})
+
+	// test
+	err := q.Dequeue(id)
+
+	// assert
+	assert.NoError(t, err)
+	work, ok := q.FindWork(id)
+	assert.True(t, ok)
+	assert.Equal(t, CANCELLED.String(), work.State())
+	assert.ErrorIs(t, work.Err(), context.Canceled)
+}
+
+func TestQueue_Retry_StateIsRetrying(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	failed := make(chan struct{})
+
+	// test
+	id, _ := q.Enqueue(func() error {
+		close(failed)
+		return errors.New("failed")
+	}, WithRetryPolicy(NewRetryPolicy(2, WithBackoff(ConstantBackoff(time.Hour)))))
+	<-failed
+
+	// assert
+	assert.Eventually(t, func() bool {
+		work, ok := q.FindWork(id)
+		return ok && work.State() == RETRYING.String() && work.Attempt() == 1
+	}, time.Second, time.Millisecond)
+	q.Cancel(id)
+	work, _ := q.FindWork(id)
+	assert.Equal(t, CANCELLED.String(), work.State())
+}
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+)
+
+// WorkHandler performs an attempt of queued work
+type WorkHandler func(ctx context.Context, work *QueuedWork) error
+
+// Interceptor wraps the handler performing work with behaviour around each attempt of the work, such as tracing, timing or adding values
+// to the work's context.  An interceptor may return without calling next to stop the work being performed.
+type Interceptor func(next WorkHandler) WorkHandler
+
+// BundleInterceptors composes the interceptors into a single interceptor.  The first interceptor is the outermost, so is the first to see
+// each attempt of work and the last to see its result.
+func BundleInterceptors(interceptors ...Interceptor) Interceptor {
+	return func(next WorkHandler) WorkHandler {
+		if len(interceptors) == 0 {
+			return next
+		}
+
+		wrapped := next
+		// loop in reverse to preserve interceptor order
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			wrapped = interceptors[i](wrapped)
+		}
+		return wrapped
+	}
+}
+
+// intercept returns the handler performing the work item, wrapped by the queue's interceptors
+func (w *Queue) intercept(wi *workItem) WorkHandler {
+	handler := func(ctx context.Context, _ *QueuedWork) error {
+		return wi.workToDo(ctx)
+	}
+	if w.interceptor == nil {
+		return handler
+	}
+	return w.interceptor(handler)
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey string
+
+func TestQueue_WithInterceptors_WrapsWorkInOrder(t *testing.T) {
+	// setup
+	mux := &sync.Mutex{}
+	calls := []string{}
+	record := func(call string) {
+		mux.Lock()
+		defer mux.Unlock()
+		calls = append(calls, call)
+	}
+	interceptor := func(name string) Interceptor {
+		return func(next WorkHandler) WorkHandler {
+			return func(ctx context.Context, work *QueuedWork) error {
+				record(name + " before " + work.Name())
+				err := next(ctx, work)
+				record(name + " after")
+				return err
+			}
+		}
+	}
+	q := NewQueue(WithWorkers(1), WithInterceptors(interceptor("outer")), WithInterceptors(interceptor("inner")))
+	defer q.Stop()
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		record("work")
+		return true, nil
+	}, WithName("job"))
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	mux.Lock()
+	defer mux.Unlock()
+	assert.Equal(t, []string{"outer before job", "inner before job", "work", "inner after", "outer after"}, calls)
+}
+
+func TestQueue_WithInterceptors_PassesContextToWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithInterceptors(func(next WorkHandler) WorkHandler {
+		return func(ctx context.Context, work *QueuedWork) error {
+			return next(context.WithValue(ctx, contextKey("user"), "randy"), work)
+		}
+	}))
+	defer q.Stop()
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (any, error) {
+		return ctx.Value(contextKey("user")), nil
+	})
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	user, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "randy", user)
+}
+
+func TestQueue_WithInterceptors_ShortCircuitsWork(t *testing.T) {
+	// setup
+	errDenied := errors.New("denied")
+	q := NewQueue(WithWorkers(1), WithInterceptors(func(next WorkHandler) WorkHandler {
+		return func(ctx context.Context, work *QueuedWork) error {
+			return errDenied
+		}
+	}))
+	defer q.Stop()
+	performed := false
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		performed = true
+		return true, nil
+	})
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := f.Wait(ctx)
+	assert.ErrorIs(t, err, errDenied)
+	assert.False(t, performed)
+}
+
+func TestBundleInterceptors_NoInterceptors_ReturnsNext(t *testing.T) {
+	// setup
+	called := false
+	next := func(ctx context.Context, work *QueuedWork) error {
+		called = true
+		return nil
+	}
+
+	// test
+	err := BundleInterceptors()(next)(context.Background(), nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
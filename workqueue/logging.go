@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogPublisher is the logging interface used by the queue.  It is satisfied by *slog.Logger.
+type LogPublisher interface {
+	InfoContext(ctx context.Context, msg string, args ...any)
+	Info(msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	Warn(msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// DebugLogPublisher is implemented by loggers that also log at debug level, such as *slog.Logger.  Events logged at a level below
+// slog.LevelInfo are only logged by loggers implementing DebugLogPublisher.
+type DebugLogPublisher interface {
+	DebugContext(ctx context.Context, msg string, args ...any)
+}
+
+// LogEvent identifies an event in the queue that is logged
+type LogEvent int
+
+// log events
+const (
+	// LogEnqueued is logged when work is enqueued
+	LogEnqueued LogEvent = iota
+	// LogBackpressure is logged when enqueuing work blocks because the queue is full, when work is dropped from a full queue, and when a
+	// batch cannot be enqueued
+	LogBackpressure
+	// LogDispatched is logged when a worker starts work
+	LogDispatched
+	// LogRetrying is logged when failed work is scheduled to be retried
+	LogRetrying
+	// LogCompleted is logged when work completes, is cancelled or is skipped
+	LogCompleted
+	// LogFailed is logged when work fails
+	LogFailed
+	// LogStorageFailed is logged when work cannot be read from, or written to, the queue's storage
+	LogStorageFailed
+	// LogPaused is logged when the queue or a group is paused or resumed
+	LogPaused
+	// LogBrokerFailed is logged when work cannot be leased from, or settled with, the queue's broker
+	LogBrokerFailed
+)
+
+// defaultLogLevels are the levels events are logged at unless configured otherwise.  Events with a level below slog.LevelInfo are only logged
+// by loggers implementing DebugLogPublisher.
+func defaultLogLevels() map[LogEvent]slog.Level {
+	return map[LogEvent]slog.Level{
+		LogEnqueued:      slog.LevelDebug,
+		LogBackpressure:  slog.LevelWarn,
+		LogDispatched:    slog.LevelDebug,
+		LogRetrying:      slog.LevelWarn,
+		LogCompleted:     slog.LevelDebug,
+		LogFailed:        slog.LevelError,
+		LogStorageFailed: slog.LevelError,
+		LogPaused:        slog.LevelInfo,
+		LogBrokerFailed:  slog.LevelError,
+	}
+}
+
+// logWork logs the event for a work item at the level configured for the event
+func (w *Queue) logWork(ctx context.Context, event LogEvent, msg string, wi *workItem, args ...any) {
+	if !w.logs(event) {
+		return
+	}
+
+	w.log(ctx, event, msg, append([]any{
+		slog.String("id", wi.Id()),
+		slog.String("name", wi.name),
+		slog.Int("priority", wi.Priority()),
+		slog.Int("attempt", wi.Attempt()),
+	}, args...)...)
+}
+
+// log logs the event at the level configured for the event
+func (w *Queue) log(ctx context.Context, event LogEvent, msg string, args ...any) {
+	if !w.logs(event) {
+		return
+	}
+
+	switch level := w.logLevels[event]; {
+	case level >= slog.LevelError:
+		w.logger.ErrorContext(ctx, msg, args...)
+	case level >= slog.LevelWarn:
+		w.logger.WarnContext(ctx, msg, args...)
+	case level >= slog.LevelInfo:
+		w.logger.InfoContext(ctx, msg, args...)
+	default:
+		w.logger.(DebugLogPublisher).DebugContext(ctx, msg, args...)
+	}
+}
+
+// logs returns true if the queue's logger logs the event at its configured level.  Events below slog.LevelDebug are never logged.
+func (w *Queue) logs(event LogEvent) bool {
+	if w.logger == nil {
+		return false
+	}
+	level := w.logLevels[event]
+	if level >= slog.LevelInfo {
+		return true
+	}
+	_, debug := w.logger.(DebugLogPublisher)
+	return debug && level >= slog.LevelDebug
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loggedMessage struct {
+	level slog.Level
+	msg   string
+}
+
+// recordingLogger records the messages logged to it
+type recordingLogger struct {
+	mux      sync.Mutex
+	messages []loggedMessage
+}
+
+func (l *recordingLogger) record(level slog.Level, msg string) {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	l.messages = append(l.messages, loggedMessage{level: level, msg: msg})
+}
+
+func (l *recordingLogger) Messages() []loggedMessage {
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	return append([]loggedMessage{}, l.messages...)
+}
+
+func (l *recordingLogger) InfoContext(_ context.Context, msg string, _ ...any) {
+	l.record(slog.LevelInfo, msg)
+}
+
+func (l *recordingLogger) Info(msg string, _ ...any) {
+	l.record(slog.LevelInfo, msg)
+}
+
+func (l *recordingLogger) WarnContext(_ context.Context, msg string, _ ...any) {
+	l.record(slog.LevelWarn, msg)
+}
+
+func (l *recordingLogger) Warn(msg string, _ ...any) {
+	l.record(slog.LevelWarn, msg)
+}
+
+func (l *recordingLogger) ErrorContext(_ context.Context, msg string, _ ...any) {
+	l.record(slog.LevelError, msg)
+}
+
+func (l *recordingLogger) Error(msg string, _ ...any) {
+	l.record(slog.LevelError, msg)
+}
+
+func TestQueue_WithLogger_DefaultLevels_LogsFailures(t *testing.T) {
+	// setup
+	logger := &recordingLogger{}
+	q := NewQueue(WithWorkers(1), WithLogger(logger))
+
+	// test
+	_, _ = q.Enqueue(func() error { return nil })
+	_, _ = q.Enqueue(func() error { return errors.New("failed") })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []loggedMessage{{level: slog.LevelError, msg: "work failed"}}, logger.Messages())
+}
+
+func TestQueue_WithLogLevel_LogsConfiguredEvents(t *testing.T) {
+	// setup
+	logger := &recordingLogger{}
+	q := NewQueue(WithWorkers(1), WithLogger(logger),
+		WithLogLevel(LogEnqueued, slog.LevelInfo),
+		WithLogLevel(LogDispatched, slog.LevelInfo),
+		WithLogLevel(LogCompleted, slog.LevelWarn),
+		WithLogLevel(LogFailed, slog.LevelDebug))
+
+	// test
+	_, _ = q.Enqueue(func() error { return nil })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []loggedMessage{
+		{level: slog.LevelInfo, msg: "work enqueued"},
+		{level: slog.LevelInfo, msg: "work started"},
+		{level: slog.LevelWarn, msg: "work finished"},
+	}, logger.Messages())
+}
+
+// debugLogger records the messages logged to it, including debug messages
+type debugLogger struct {
+	recordingLogger
+}
+
+func (l *debugLogger) DebugContext(_ context.Context, msg string, _ ...any) {
+	l.record(slog.LevelDebug, msg)
+}
+
+func TestQueue_WithLogger_DebugLogger_LogsDebugEvents(t *testing.T) {
+	// setup
+	logger := &debugLogger{}
+	q := NewQueue(WithWorkers(1), WithLogger(logger))
+
+	// test
+	_, _ = q.Enqueue(func() error { return nil })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []loggedMessage{
+		{level: slog.LevelDebug, msg: "work enqueued"},
+		{level: slog.LevelDebug, msg: "work started"},
+		{level: slog.LevelDebug, msg: "work finished"},
+	}, logger.Messages())
+}
+
+func TestQueue_WithLogger_SlogLogger_LogsDebugEvents(t *testing.T) {
+	// setup
+	buffer := &strings.Builder{}
+	logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	q := NewQueue(WithWorkers(1), WithLogger(logger))
+
+	// test
+	_, _ = q.Enqueue(func() error { return nil })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Contains(t, buffer.String(), "level=DEBUG msg=\"work enqueued\"")
+}
+
+func TestQueue_WithLogger_LogsBackpressure(t *testing.T) {
+	// setup
+	logger := &recordingLogger{}
+	q := NewQueue(WithWorkers(1), WithQueueLength(0), WithLogger(logger))
+	defer q.Stop()
+	release := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		<-release
+		return nil
+	})
+
+	// test
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		close(release)
+	}()
+	_, err := q.Enqueue(func() error { return nil })
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, logger.Messages(), loggedMessage{level: slog.LevelWarn, msg: "queue full, waiting for free worker"})
+}
@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// brokeredWork is work held by a MemoryBroker along with its lease
+type brokeredWork struct {
+	work       *StoredWork
+	seq        uint64
+	visibleAt  time.Time
+	token      uuid.UUID
+	deliveries int
+}
+
+// MemoryBroker is a Broker holding work in memory, shared by queues in the same process or served to queues in other processes with
+// ListenBroker.  Work is leased by priority, then in the order it was enqueued.
+type MemoryBroker struct {
+	mux       *sync.Mutex
+	clock     Clock
+	work      map[uuid.UUID]*brokeredWork
+	leases    map[uuid.UUID]*brokeredWork
+	seq       uint64
+	available chan struct{}
+}
+
+// NewMemoryBroker returns a reference to an initialized MemoryBroker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		mux:       &sync.Mutex{},
+		clock:     realClock{},
+		work:      map[uuid.UUID]*brokeredWork{},
+		leases:    map[uuid.UUID]*brokeredWork{},
+		available: make(chan struct{}),
+	}
+}
+
+// Enqueue adds work to the broker.  Work enqueued with the id of work the broker holds replaces it.
+func (b *MemoryBroker) Enqueue(_ context.Context, work *StoredWork) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if existing, ok := b.work[work.Id]; ok {
+		delete(b.leases, existing.token)
+	}
+	b.seq++
+	b.work[work.Id] = &brokeredWork{work: work, seq: b.seq, visibleAt: work.RunAt}
+	b.signalAvailable()
+	return nil
+}
+
+// Lease blocks until work is available, or ctx is done, returning the due work of highest priority hidden from other consumers for the
+// visibility timeout
+func (b *MemoryBroker) Lease(ctx context.Context, visibility time.Duration) (*Lease, error) {
+	for {
+		b.mux.Lock()
+		now := b.clock.Now()
+		next, wake := b.next(now)
+		if next != nil {
+			delete(b.leases, next.token)
+			next.token = uuid.New()
+			next.deliveries++
+			next.visibleAt = now.Add(visibility)
+			b.leases[next.token] = next
+			b.mux.Unlock()
+			return &Lease{Token: next.token, Work: next.work, Deliveries: next.deliveries, Expires: next.visibleAt}, nil
+		}
+		available := b.available
+		b.mux.Unlock()
+
+		// a nil channel never wakes the consumer when no work is delayed
+		var woken chan struct{}
+		stop := func() bool { return false }
+		if !wake.IsZero() {
+			woken = make(chan struct{})
+			stop = b.clock.AfterFunc(wake.Sub(now), func() {
+				close(woken)
+			})
+		}
+		select {
+		case <-available:
+		case <-woken:
+		case <-ctx.Done():
+			stop()
+			return nil, ctx.Err()
+		}
+		stop()
+	}
+}
+
+// Ack removes leased work once it has been performed
+func (b *MemoryBroker) Ack(_ context.Context, token uuid.UUID) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	bw, err := b.leased(token)
+	if err != nil {
+		return err
+	}
+	delete(b.leases, token)
+	delete(b.work, bw.work.Id)
+	return nil
+}
+
+// Nack releases leased work that was not performed to be delivered again after the delay
+func (b *MemoryBroker) Nack(_ context.Context, token uuid.UUID, delay time.Duration) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	bw, err := b.leased(token)
+	if err != nil {
+		return err
+	}
+	delete(b.leases, token)
+	bw.token = uuid.Nil
+	bw.visibleAt = b.clock.Now().Add(delay)
+	b.signalAvailable()
+	return nil
+}
+
+// ExtendLease hides leased work from other consumers for the visibility timeout from now
+func (b *MemoryBroker) ExtendLease(_ context.Context, token uuid.UUID, visibility time.Duration) error {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	bw, err := b.leased(token)
+	if err != nil {
+		return err
+	}
+	bw.visibleAt = b.clock.Now().Add(visibility)
+	return nil
+}
+
+// Len returns the number of work items held by the broker, including work that is leased
+func (b *MemoryBroker) Len() int {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	return len(b.work)
+}
+
+// leased returns the work leased with the token, returning ErrLeaseExpired if the lease has expired.  mux must be held.
+func (b *MemoryBroker) leased(token uuid.UUID) (*brokeredWork, error) {
+	bw, ok := b.leases[token]
+	if !ok || !b.clock.Now().Before(bw.visibleAt) {
+		return nil, ErrLeaseExpired
+	}
+	return bw, nil
+}
+
+// next returns the visible work of highest priority, or if no work is visible the time work next becomes visible.  mux must be held.
+func (b *MemoryBroker) next(now time.Time) (*brokeredWork, time.Time) {
+	var next *brokeredWork
+	var wake time.Time
+	for _, bw := range b.work {
+		if bw.visibleAt.After(now) {
+			if wake.IsZero() || bw.visibleAt.Before(wake) {
+				wake = bw.visibleAt
+			}
+			continue
+		}
+		if next == nil || bw.work.Priority < next.work.Priority || (bw.work.Priority == next.work.Priority && bw.seq < next.seq) {
+			next = bw
+		}
+	}
+	return next, wake
+}
+
+// signalAvailable wakes consumers waiting for work.  mux must be held.
+func (b *MemoryBroker) signalAvailable() {
+	close(b.available)
+	b.available = make(chan struct{})
+}
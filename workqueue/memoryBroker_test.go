@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBroker_Lease_ByPriorityThenEnqueueOrder(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	ctx := context.Background()
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New(), Name: "low", Priority: 5})
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New(), Name: "first", Priority: 1})
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New(), Name: "second", Priority: 1})
+
+	// test
+	names := []string{}
+	for i := 0; i < 3; i++ {
+		lease, err := b.Lease(ctx, time.Minute)
+		assert.NoError(t, err)
+		names = append(names, lease.Work.Name)
+	}
+
+	// assert
+	assert.Equal(t, []string{"first", "second", "low"}, names)
+}
+
+func TestMemoryBroker_Lease_BlocksUntilWorkIsDue(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New(), RunAt: time.Now().Add(time.Millisecond * 50)})
+	go func() {
+		time.Sleep(time.Millisecond * 20)
+		_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New(), Name: "later", RunAt: time.Now().Add(time.Hour)})
+	}()
+
+	// test
+	start := time.Now()
+	lease, err := b.Lease(ctx, time.Minute)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, lease.Work.Name)
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*50)
+}
+
+func TestMemoryBroker_Lease_ExpiredLeaseIsDeliveredAgain(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New()})
+	first, _ := b.Lease(ctx, time.Millisecond*20)
+
+	// test
+	second, err := b.Lease(ctx, time.Minute)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, first.Work.Id, second.Work.Id)
+	assert.Equal(t, 2, second.Deliveries)
+	assert.ErrorIs(t, b.Ack(ctx, first.Token), ErrLeaseExpired)
+	assert.NoError(t, b.Ack(ctx, second.Token))
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestMemoryBroker_Nack_DeliversWorkAgain(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = b.Enqueue(ctx, &StoredWork{Id: uuid.New()})
+	first, _ := b.Lease(ctx, time.Minute)
+
+	// test
+	err := b.Nack(ctx, first.Token, 0)
+	second, leaseErr := b.Lease(ctx, time.Minute)
+
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, leaseErr)
+	assert.Equal(t, first.Work.Id, second.Work.Id)
+	assert.ErrorIs(t, b.Nack(ctx, first.Token, 0), ErrLeaseExpired)
+}
+
+func TestMemoryBroker_ExtendLease_HidesWorkFromOtherConsumers(t *testing.T) {
+	// setup
+	b := NewMemoryBroker()
+	_ = b.Enqueue(context.Background(), &StoredWork{Id: uuid.New()})
+	lease, _ := b.Lease(context.Background(), time.Millisecond*50)
+
+	// test
+	err := b.ExtendLease(context.Background(), lease.Token, time.Minute)
+	time.Sleep(time.Millisecond * 60)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, leaseErr := b.Lease(ctx, time.Minute)
+
+	// assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, leaseErr, context.DeadlineExceeded)
+	assert.NoError(t, b.Ack(context.Background(), lease.Token))
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics observes work moving through a queue.  Implementations are called from the goroutines enqueuing and performing work and must be
+// safe for concurrent use.
+type Metrics interface {
+	// OnEnqueued is called when work is enqueued
+	OnEnqueued(work *QueuedWork)
+	// OnStarted is called when an attempt of work starts, with how long the work waited in the queue
+	OnStarted(work *QueuedWork, waited time.Duration)
+	// OnFinished is called when work finishes in a final state, with how long the last attempt of the work ran
+	OnFinished(work *QueuedWork, ran time.Duration)
+}
+
+// queueObserver is implemented by metrics that report on the state of the queue they are attached to
+type queueObserver interface {
+	observeQueue(queue *Queue)
+}
+
+// Stats is a snapshot of a queue's state and the work it has performed
+type Stats struct {
+	Workers     int `json:"workers"`
+	BusyWorkers int `json:"busyWorkers"`
+	Queued      int `json:"queued"`
+	Scheduled   int `json:"scheduled"`
+	QueueLength int `json:"queueLength"`
+
+	Enqueued  uint64 `json:"enqueued"`
+	Started   uint64 `json:"started"`
+	Retried   uint64 `json:"retried"`
+	Completed uint64 `json:"completed"`
+	Failed    uint64 `json:"failed"`
+	Cancelled uint64 `json:"cancelled"`
+	Skipped   uint64 `json:"skipped"`
+
+	// AverageWait is the average time work waited in the queue before starting
+	AverageWait time.Duration `json:"averageWait"`
+	// AverageRunTime is the average time attempts of work ran
+	AverageRunTime time.Duration `json:"averageRunTime"`
+}
+
+// Finished returns the number of work items that finished in a final state
+func (s Stats) Finished() uint64 {
+	return s.Completed + s.Failed + s.Cancelled + s.Skipped
+}
+
+// ErrorRate returns the fraction of finished work that failed
+func (s Stats) ErrorRate() float64 {
+	finished := s.Finished()
+	if finished == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(finished)
+}
+
+// queueStats accumulates the counters reported in a queue's Stats
+type queueStats struct {
+	enqueued  atomic.Uint64
+	started   atomic.Uint64
+	retried   atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	cancelled atomic.Uint64
+	skipped   atomic.Uint64
+	ran       atomic.Uint64
+	waitTotal atomic.Int64
+	runTotal  atomic.Int64
+}
+
+func (s *queueStats) finished(state workState) {
+	switch state {
+	case COMPLETED:
+		s.completed.Add(1)
+	case FAILED:
+		s.failed.Add(1)
+	case CANCELLED:
+		s.cancelled.Add(1)
+	case SKIPPED:
+		s.skipped.Add(1)
+	}
+}
+
+// Stats returns a snapshot of the queue's state and the work it has performed
+func (w *Queue) Stats() Stats {
+	w.queueMux.Lock()
+	stats := Stats{
+		Workers:     w.workerCount,
+		BusyWorkers: w.busy,
+		Queued:      w.workQueue.Len(),
+		Scheduled:   w.scheduled.Len(),
+		QueueLength: int(w.queueLength.Load()),
+	}
+	w.queueMux.Unlock()
+
+	stats.Enqueued = w.stats.enqueued.Load()
+	stats.Started = w.stats.started.Load()
+	stats.Retried = w.stats.retried.Load()
+	stats.Completed = w.stats.completed.Load()
+	stats.Failed = w.stats.failed.Load()
+	stats.Cancelled = w.stats.cancelled.Load()
+	stats.Skipped = w.stats.skipped.Load()
+	if stats.Started > 0 {
+		stats.AverageWait = time.Duration(w.stats.waitTotal.Load() / int64(stats.Started))
+	}
+	if ran := w.stats.ran.Load(); ran > 0 {
+		stats.AverageRunTime = time.Duration(w.stats.runTotal.Load() / int64(ran))
+	}
+	return stats
+}
+
+func (w *Queue) onEnqueued(wi *workItem) {
+	w.stats.enqueued.Add(1)
+	if w.metrics != nil {
+		w.metrics.OnEnqueued(wi.QueuedWork)
+	}
+}
+
+func (w *Queue) onStarted(wi *workItem, waited time.Duration) {
+	w.stats.started.Add(1)
+	w.stats.waitTotal.Add(int64(waited))
+	if w.metrics != nil {
+		w.metrics.OnStarted(wi.QueuedWork, waited)
+	}
+}
+
+// onAttemptFinished records the run time of an attempt of work
+func (w *Queue) onAttemptFinished(ran time.Duration) {
+	w.stats.ran.Add(1)
+	w.stats.runTotal.Add(int64(ran))
+}
+
+func (w *Queue) onFinished(wi *workItem, state workState) {
+	w.stats.finished(state)
+	if w.metrics != nil {
+		w.metrics.OnFinished(wi.QueuedWork, wi.RunTime())
+	}
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingMetrics records the names of the work observed by each Metrics callback
+type recordingMetrics struct {
+	mux      sync.Mutex
+	enqueued []string
+	started  []string
+	finished []string
+}
+
+func (m *recordingMetrics) OnEnqueued(work *QueuedWork) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.enqueued = append(m.enqueued, work.Name())
+}
+
+func (m *recordingMetrics) OnStarted(work *QueuedWork, _ time.Duration) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.started = append(m.started, work.Name())
+}
+
+func (m *recordingMetrics) OnFinished(work *QueuedWork, _ time.Duration) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.finished = append(m.finished, work.Name()+" "+work.State())
+}
+
+func TestQueue_WithMetrics_ObservesWork(t *testing.T) {
+	// setup
+	metrics := &recordingMetrics{}
+	q := NewQueue(WithWorkers(1), WithMetrics(metrics))
+
+	// test
+	_, _ = q.Enqueue(func() error { return nil }, WithName("work1"))
+	_, _ = q.Enqueue(func() error { return errors.New("failed") }, WithName("work2"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []string{"work1", "work2"}, metrics.enqueued)
+	assert.Equal(t, []string{"work1", "work2"}, metrics.started)
+	assert.Equal(t, []string{"work1 Completed", "work2 Failed"}, metrics.finished)
+}
+
+func TestQueue_Stats(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2), WithQueueLength(5), WithDefaultRetryPolicy(NewRetryPolicy(2)))
+	release := make(chan struct{})
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(func() error {
+			<-release
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+	}
+	_, _ = q.Enqueue(func() error { return errors.New("failed") })
+
+	// test
+	var busy Stats
+	assert.Eventually(t, func() bool {
+		busy = q.Stats()
+		return busy.BusyWorkers == 2
+	}, time.Second, time.Millisecond)
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+	stats := q.Stats()
+
+	// assert
+	assert.Equal(t, 2, busy.Workers)
+	assert.Equal(t, 2, busy.Queued)
+	assert.Equal(t, 5, busy.QueueLength)
+	assert.Equal(t, uint64(4), stats.Enqueued)
+	assert.Equal(t, uint64(5), stats.Started)
+	assert.Equal(t, uint64(1), stats.Retried)
+	assert.Equal(t, uint64(3), stats.Completed)
+	assert.Equal(t, uint64(1), stats.Failed)
+	assert.Equal(t, uint64(4), stats.Finished())
+	assert.Equal(t, 0.25, stats.ErrorRate())
+	assert.Equal(t, 0, stats.BusyWorkers)
+	assert.Equal(t, 0, stats.Queued)
+	assert.Greater(t, stats.AverageRunTime, time.Duration(0))
+	assert.Greater(t, stats.AverageWait, time.Duration(0))
+}
+
+func TestStats_ErrorRate_NothingFinished_ReturnsZero(t *testing.T) {
+	assert.Equal(t, float64(0), Stats{}.ErrorRate())
+}
@@ -7,7 +7,11 @@
 package workqueue
 
 import (
+	"context"
+	"encoding/json"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,6 +22,13 @@ type workState int32
 const (
 	IN_QUEUE workState = iota
 	IN_PROGRESS
+	COMPLETED
+	FAILED
+	CANCELLED
+	RETRYING
+	SKIPPED
+	SCHEDULED
+	WAITING
 )
 
 func (ws workState) String() string {
@@ -26,12 +37,29 @@ func (ws workState) String() string {
 		return "In Progress"
 	case IN_QUEUE:
 		return "Queued"
+	case COMPLETED:
+		return "Completed"
+	case FAILED:
+		return "Failed"
+	case CANCELLED:
+		return "Cancelled"
+	case RETRYING:
+		return "Retrying"
+	case SKIPPED:
+		return "Skipped"
+	case SCHEDULED:
+		return "Scheduled"
+	case WAITING:
+		return "Waiting"
 	}
 	return "unknown"
 }
 
 type Work func() error
 
+// ContextWork is work that receives a context which is cancelled when the work is cancelled, its deadline passes or the queue is stopped
+type ContextWork func(ctx context.Context) error
+
 type WorkQueueOption func(*Queue)
 
 type QueuedWork struct {
@@ -40,6 +68,15 @@ type QueuedWork struct {
 	priority int
 	position int
 	state    *atomic.Int32
+	attempts atomic.Int32
+	runAt    time.Time
+	clock    Clock
+
+	mux        sync.RWMutex
+	enqueuedAt time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	err        error
 }
 
 func (w *QueuedWork) Id() string {
@@ -51,16 +88,189 @@ func (w *QueuedWork) Name() string {
 }
 
 func (w *QueuedWork) Priority() int {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
 	return w.priority
 }
 
+// setPriority sets the priority of the work.  Work on the prioritized queue must only have its priority set while holding the queue's lock.
+func (w *QueuedWork) setPriority(priority int) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.priority = priority
+}
+
+// Attempt returns the number of times the work has been started
+func (w *QueuedWork) Attempt() int {
+	return int(w.attempts.Load())
+}
+
 func (w *QueuedWork) State() string {
 	st := workState(w.state.Load())
 	return st.String()
 }
 
+// EnqueuedAt returns the time the work was enqueued
+func (w *QueuedWork) EnqueuedAt() time.Time {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.enqueuedAt
+}
+
+// RunAt returns the time delayed or scheduled work is due, or the zero time if the work may run as soon as a worker is free
+func (w *QueuedWork) RunAt() time.Time {
+	return w.runAt
+}
+
+// StartedAt returns the time the latest attempt of the work started, or the zero time if the work has not started
+func (w *QueuedWork) StartedAt() time.Time {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.startedAt
+}
+
+// FinishedAt returns the time the work finished, or the zero time if the work has not finished
+func (w *QueuedWork) FinishedAt() time.Time {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.finishedAt
+}
+
+// RunTime returns how long the latest attempt of the work ran (or has been running)
+func (w *QueuedWork) RunTime() time.Duration {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	if w.startedAt.IsZero() {
+		return 0
+	}
+	if w.finishedAt.IsZero() {
+		return w.clock.Now().Sub(w.startedAt)
+	}
+	return w.finishedAt.Sub(w.startedAt)
+}
+
+// Err returns the error the work failed or was cancelled with
+func (w *QueuedWork) Err() error {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.err
+}
+
+// MarshalJSON encodes a snapshot of the work's details and state
+func (w *QueuedWork) MarshalJSON() ([]byte, error) {
+	type queuedWorkJson struct {
+		Id         string    `json:"id"`
+		Name       string    `json:"name,omitempty"`
+		Priority   int       `json:"priority"`
+		State      string    `json:"state"`
+		Attempt    int       `json:"attempt"`
+		EnqueuedAt time.Time `json:"enqueuedAt"`
+		RunAt      time.Time `json:"runAt"`
+		StartedAt  time.Time `json:"startedAt"`
+		FinishedAt time.Time `json:"finishedAt"`
+		Error      string    `json:"error,omitempty"`
+	}
+
+	j := queuedWorkJson{
+		Id:         w.Id(),
+		Name:       w.name,
+		Priority:   w.Priority(),
+		State:      w.State(),
+		Attempt:    w.Attempt(),
+		EnqueuedAt: w.EnqueuedAt(),
+		RunAt:      w.runAt,
+		StartedAt:  w.StartedAt(),
+		FinishedAt: w.FinishedAt(),
+	}
+	if err := w.Err(); err != nil {
+		j.Error = err.Error()
+	}
+	return json.Marshal(j)
+}
+
+func (w *QueuedWork) started() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.startedAt = w.clock.Now()
+}
+
+func (w *QueuedWork) finished(state workState, err error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.finishedAt = w.clock.Now()
+	w.err = err
+	w.state.Store(int32(state))
+}
+
 type workItem struct {
 	*QueuedWork
-	workToDo       Work
+	workToDo       ContextWork
 	adjustPriority func() int
+	ctx            context.Context
+	parentCtx      context.Context
+	cancel         context.CancelFunc
+	timeout        time.Duration
+	deadline       time.Time
+	delay          time.Duration
+	stepDone       chan struct{}
+	retryPolicy    *RetryPolicy
+	onFinish       func(err error)
+	queuedAt       time.Time
+	storage        Storage
+	dependsOn      []uuid.UUID
+	waitingOn      int
+	group          string
+	tenant         string
+	uniqueKey      string
+	coalescedInto  *QueuedWork
+}
+
+func newWorkItem(id uuid.UUID, workToDo ContextWork, options ...workOption) *workItem {
+	wi := &workItem{
+		QueuedWork: &QueuedWork{
+			id:       id,
+			priority: 1,
+			position: -1,
+			state:    &atomic.Int32{},
+			clock:    realClock{},
+		},
+
+		workToDo: workToDo,
+	}
+	for _, option := range options {
+		option(wi)
+	}
+	return wi
+}
+
+// finish cancels the work item's context and notifies any observer of the work item's outcome
+func (wi *workItem) finish(err error) {
+	wi.cancel()
+	if wi.onFinish != nil {
+		wi.onFinish(err)
+	}
+}
+
+// workContext returns the context the work item is executed with, applying the item's deadline and timeout and cancelling it if the queue context is cancelled
+func (wi *workItem) workContext(queueCtx context.Context, clock Clock) (context.Context, context.CancelFunc) {
+	ctx := wi.ctx
+	cancels := []context.CancelFunc{}
+	if !wi.deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithDeadline(ctx, wi.deadline)
+		cancels = append(cancels, cancel)
+	}
+	if wi.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = clock.WithDeadline(ctx, clock.Now().Add(wi.timeout))
+		cancels = append(cancels, cancel)
+	}
+	stopAfter := context.AfterFunc(queueCtx, wi.cancel)
+
+	return ctx, func() {
+		stopAfter()
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
 }
@@ -6,6 +6,13 @@
 
 package workqueue
 
+import (
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
 // WithWorkers sets the number of go routines working on the workChan
 func WithWorkers(workerCount int) WorkQueueOption {
 	return func(queue *Queue) {
@@ -20,6 +27,193 @@ func WithQueueLength(queueLength int) WorkQueueOption {
 	}
 }
 
+// WithDefaultRetryPolicy sets the retry policy used for work enqueued without a retry policy of its own
+func WithDefaultRetryPolicy(policy *RetryPolicy) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.retryPolicy = policy
+	}
+}
+
+// WithHistorySize sets the number of finished work items retained in the queue's history.  A size of zero disables the history.
+func WithHistorySize(size int) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.historySize = size
+	}
+}
+
+// WithLogger sets the logger used to log queue events.  By default the queue does not log.
+func WithLogger(logger LogPublisher) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.logger = logger
+	}
+}
+
+// WithLogLevel sets the level an event is logged at.  Events with a level below slog.LevelInfo are only logged by loggers implementing
+// DebugLogPublisher, such as *slog.Logger, and events below slog.LevelDebug are not logged.
+func WithLogLevel(event LogEvent, level slog.Level) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.logLevels[event] = level
+	}
+}
+
+// WithMetrics sets the metrics observing work moving through the queue
+func WithMetrics(metrics Metrics) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.metrics = metrics
+		if observer, ok := metrics.(queueObserver); ok {
+			observer.observeQueue(queue)
+		}
+	}
+}
+
+// WithStorage sets the storage persisting work enqueued with EnqueueHandler.  Work left in the storage is replayed when the queue is created.
+func WithStorage(storage Storage) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.storage = storage
+	}
+}
+
+// WithHandlers sets the registry of handlers performing work enqueued with EnqueueHandler
+func WithHandlers(registry *HandlerRegistry) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.handlers = registry
+	}
+}
+
+// WithRateLimit limits the rate work is started on the queue to perSecond, allowing bursts of up to burst work to start at once.  The
+// option is ignored unless perSecond is positive and burst is at least 1.
+func WithRateLimit(perSecond float64, burst int) WorkQueueOption {
+	return func(queue *Queue) {
+		if validRateLimit(perSecond, burst) {
+			queue.limiter = newTokenBucket(perSecond, burst)
+		}
+	}
+}
+
+// WithGroupConcurrency limits the number of work items enqueued in the group that are performed at once
+func WithGroupConcurrency(group string, maxConcurrency int) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.group(group).maxConcurrency = maxConcurrency
+	}
+}
+
+// WithGroupRateLimit limits the rate work enqueued in the group is started to perSecond, allowing bursts of up to burst work to start at once.
+// The option is ignored unless perSecond is positive and burst is at least 1.
+func WithGroupRateLimit(group string, perSecond float64, burst int) WorkQueueOption {
+	return func(queue *Queue) {
+		if validRateLimit(perSecond, burst) {
+			queue.group(group).limiter = newTokenBucket(perSecond, burst)
+		}
+	}
+}
+
+// WithPriorityAging boosts the priority of work waiting on the queue by one for each interval it has waited, so a steady stream of high
+// priority work cannot starve lower priority work
+func WithPriorityAging(interval time.Duration) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.agingInterval = interval
+	}
+}
+
+// WithFairScheduling shares the queue's workers between tenants in proportion to their weights, rather than strictly by priority.  Work
+// enqueued with WithTenant is performed by priority within its tenant.  Tenants without a weight, including work enqueued without a
+// tenant, have a weight of 1.
+func WithFairScheduling(weights map[string]int) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.tenantWeights = map[string]int{}
+		for tenant, weight := range weights {
+			queue.tenantWeights[tenant] = weight
+		}
+	}
+}
+
+// WithOverflowPolicy sets what happens when work is enqueued on a full queue.  Queues block until there is room unless configured otherwise.
+func WithOverflowPolicy(policy OverflowPolicy) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.overflowPolicy = policy
+	}
+}
+
+// WithSpillQueue enqueues work on the secondary queue when the queue is full
+func WithSpillQueue(spill *Queue) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.overflowPolicy = SpillWhenFull
+		queue.spill = spill
+	}
+}
+
+// WithInterceptors wraps each attempt of work performed by the queue with the interceptors, the first interceptor being the outermost.
+// Interceptors are added to any the queue already has.
+func WithInterceptors(interceptors ...Interceptor) WorkQueueOption {
+	return func(queue *Queue) {
+		if queue.interceptor != nil {
+			interceptors = append([]Interceptor{queue.interceptor}, interceptors...)
+		}
+		queue.interceptor = BundleInterceptors(interceptors...)
+	}
+}
+
+// WithBroker consumes work from the broker, performing it with the handlers registered with WithHandlers.  Work is leased from the
+// broker while the queue has room for it, and its lease is extended until it has finished.
+func WithBroker(broker Broker) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.broker = broker
+	}
+}
+
+// WithVisibilityTimeout sets how long work leased from the queue's broker is hidden from other consumers before its lease is extended.
+// Leased work is delivered again if the queue fails to extend its lease within the timeout.  Defaults to 30 seconds, which a timeout of
+// zero or less leaves in place.
+func WithVisibilityTimeout(timeout time.Duration) WorkQueueOption {
+	return func(queue *Queue) {
+		if timeout > 0 {
+			queue.visibility = timeout
+		}
+	}
+}
+
+// WithRedelivery sets how many times failed work leased from the queue's broker is delivered before it is dead lettered, and the backoff
+// delaying its redelivery, given the delivery that failed.  A maxDeliveries of zero redelivers failed work until it succeeds.  Defaults to
+// 10 deliveries backing off exponentially from a second up to a minute.
+func WithRedelivery(maxDeliveries int, backoff Backoff) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.maxDeliveries = maxDeliveries
+		queue.redeliveryDelay = backoff
+	}
+}
+
+// WithDeadLetterBroker enqueues work leased from the queue's broker that failed on its final delivery on the dead letter broker.  Without a
+// dead letter broker such work is logged and removed from the broker.
+func WithDeadLetterBroker(broker Broker) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.deadLetterBroker = broker
+	}
+}
+
+// WithClock sets the clock the queue tells the time with, determining when delayed work is due, when failed work is retried, when work
+// times out and the times work is enqueued, started and finished.  Intended for tests controlling time, see package workqueuetest.
+func WithClock(clock Clock) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.clock = clock
+	}
+}
+
+// WithManualDispatch stops the queue dispatching work to workers until Step is called, so tests can perform work one item at a time.  Work
+// remaining on the queue is still performed when the queue is stopped.
+func WithManualDispatch() WorkQueueOption {
+	return func(queue *Queue) {
+		queue.manual = true
+	}
+}
+
+// WithDuplicatePolicy sets what happens when work is enqueued with the unique key of work that has not started.  By default duplicate work is
+// coalesced into the existing work.
+func WithDuplicatePolicy(policy DuplicatePolicy) WorkQueueOption {
+	return func(queue *Queue) {
+		queue.duplicatePolicy = policy
+	}
+}
+
 // WithPriority sets the priority of the work to be done.  Lower number is higher priority.
 func WithPriority(priority int) workOption {
 	return func(w *workItem) {
@@ -40,3 +234,71 @@ func WithName(name string) workOption {
 		item.name = name
 	}
 }
+
+// WithTimeout cancels the context passed to the work if the work has not completed within the duration after it starts
+func WithTimeout(timeout time.Duration) workOption {
+	return func(item *workItem) {
+		item.timeout = timeout
+	}
+}
+
+// WithDeadline cancels the context passed to the work if the work has not completed by the deadline
+func WithDeadline(deadline time.Time) workOption {
+	return func(item *workItem) {
+		item.deadline = deadline
+	}
+}
+
+// WithRunAt delays the work until the time.  The work is not eligible to be taken off the queue by a worker until it is due.
+func WithRunAt(runAt time.Time) workOption {
+	return func(item *workItem) {
+		item.runAt = runAt
+		item.delay = 0
+	}
+}
+
+// WithDelay delays the work until the duration after it is enqueued
+func WithDelay(delay time.Duration) workOption {
+	return func(item *workItem) {
+		item.delay = delay
+	}
+}
+
+// WithDependsOn holds the work out of the queue until the work with the ids has completed successfully.  If any of the work it depends on
+// fails, or is cancelled or skipped, the work is cancelled with ErrDependencyFailed.  The work depended on must be queued or in the queue's
+// history when the work is enqueued.
+func WithDependsOn(ids ...uuid.UUID) workOption {
+	return func(item *workItem) {
+		item.dependsOn = append(item.dependsOn, ids...)
+	}
+}
+
+// WithGroup enqueues the work in the group, limiting it by the group's concurrency and rate limits.  While the group is at its limits, work
+// in the group is passed over in favor of other work in the queue.
+func WithGroup(group string) workOption {
+	return func(item *workItem) {
+		item.group = group
+	}
+}
+
+// WithTenant sets the tenant the work is performed for, sharing the queue fairly with other tenants when the queue uses fair scheduling
+func WithTenant(tenant string) workOption {
+	return func(item *workItem) {
+		item.tenant = tenant
+	}
+}
+
+// WithUniqueKey identifies the logical work being done, so enqueuing work with the same key as work that has not yet started is handled by
+// the queue's duplicate policy.  Once work has started, work with the same key is queued as normal.
+func WithUniqueKey(key string) workOption {
+	return func(item *workItem) {
+		item.uniqueKey = key
+	}
+}
+
+// WithRetryPolicy sets the retry policy used when the work returns an error, overriding the queue's default retry policy
+func WithRetryPolicy(policy *RetryPolicy) workOption {
+	return func(item *workItem) {
+		item.retryPolicy = policy
+	}
+}
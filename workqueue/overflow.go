@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrQueueFull is returned when work cannot be queued because the queue is full and the work may not wait for room
+var ErrQueueFull = errors.New("queue full")
+
+// ErrWorkDropped is the error of queued work cancelled to make room for new work when the queue was full
+var ErrWorkDropped = errors.New("work dropped")
+
+// OverflowPolicy determines what happens when work is enqueued on a full queue
+type OverflowPolicy int
+
+// overflow policies
+const (
+	// BlockWhenFull waits for room in the queue
+	BlockWhenFull OverflowPolicy = iota
+	// RejectWhenFull returns ErrQueueFull
+	RejectWhenFull
+	// DropLowestPriority cancels the queued work of lowest priority with ErrWorkDropped if the new work is of a higher priority, otherwise
+	// returns ErrQueueFull.  Of work with the same priority, the work queued most recently is dropped.
+	DropLowestPriority
+	// DropOldest cancels the work that has been queued longest with ErrWorkDropped
+	DropOldest
+	// SpillWhenFull enqueues the work on the secondary queue set with WithSpillQueue, where the secondary queue's overflow policy applies
+	SpillWhenFull
+)
+
+// TryEnqueue queues work to be processed without waiting for room in the queue.  If the queue is full, the queue's overflow policy is
+// applied, except that ErrQueueFull is returned rather than blocking.  If the queue has been stopped ErrQueueStopped is returned.
+func (w *Queue) TryEnqueue(workToDo Work, options ...workOption) (uuid.UUID, error) {
+	return w.TryEnqueueContext(context.Background(), func(context.Context) error {
+		return workToDo()
+	}, options...)
+}
+
+// TryEnqueueContext queues context aware work to be processed without waiting for room in the queue.  If the queue is full, the queue's
+// overflow policy is applied, except that ErrQueueFull is returned rather than blocking.  If the queue has been stopped ErrQueueStopped is
+// returned.
+func (w *Queue) TryEnqueueContext(ctx context.Context, workToDo ContextWork, options ...workOption) (uuid.UUID, error) {
+	ids, err := w.enqueueItems(ctx, []*workItem{w.newWorkItem(uuid.New(), workToDo, options...)}, false)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ids[0], nil
+}
+
+// overflowVictim returns the queued work to drop to make room for the work item under the policy, or nil if no work should be dropped.
+// queueMux must be held.
+func (w *Queue) overflowVictim(wi *workItem, policy OverflowPolicy) *workItem {
+	var victim *workItem
+	for _, queued := range w.workQueue.Items() {
+		switch {
+		case victim == nil:
+			victim = queued
+		case policy == DropOldest:
+			if queued.queuedAt.Before(victim.queuedAt) {
+				victim = queued
+			}
+		case queued.priority > victim.priority || (queued.priority == victim.priority && queued.queuedAt.After(victim.queuedAt)):
+			victim = queued
+		}
+	}
+	if victim != nil && policy == DropLowestPriority && victim.priority <= wi.priority {
+		return nil
+	}
+	return victim
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fullQueue returns a paused queue of the length, filled with work of the priorities
+func fullQueue(t *testing.T, priorities []int, options ...WorkQueueOption) *Queue {
+	q := NewQueue(append([]WorkQueueOption{WithWorkers(1), WithQueueLength(len(priorities))}, options...)...)
+	q.Pause()
+	for _, p := range priorities {
+		_, err := q.Enqueue(func() error { return nil }, WithPriority(p))
+		assert.NoError(t, err)
+	}
+	return q
+}
+
+func TestQueue_TryEnqueue_Full_ReturnsErrQueueFull(t *testing.T) {
+	// setup
+	q := fullQueue(t, []int{1})
+	defer q.Stop()
+
+	// test
+	_, err := q.TryEnqueue(func() error { return nil })
+
+	// assert
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.Equal(t, 1, q.Stats().Queued)
+}
+
+func TestQueue_TryEnqueue_NotFull_QueuesWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+
+	// test
+	f := make(chan struct{})
+	id, err := q.TryEnqueue(func() error {
+		close(f)
+		return nil
+	})
+
+	// assert
+	assert.NoError(t, err)
+	assert.NotEqual(t, "", id.String())
+	select {
+	case <-f:
+	case <-time.After(time.Second):
+		assert.Fail(t, "work was not performed")
+	}
+}
+
+func TestQueue_WithOverflowPolicy_Reject(t *testing.T) {
+	// setup
+	q := fullQueue(t, []int{1}, WithOverflowPolicy(RejectWhenFull))
+	defer q.Stop()
+
+	// test
+	_, err := q.Enqueue(func() error { return nil })
+
+	// assert
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestQueue_WithOverflowPolicy_DropLowestPriority(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(3), WithOverflowPolicy(DropLowestPriority))
+	defer q.Stop()
+	q.Pause()
+	_, _ = q.Enqueue(func() error { return nil }, WithPriority(3))
+	_, _ = q.Enqueue(func() error { return nil }, WithPriority(5))
+	lowest, _ := q.Enqueue(func() error { return nil }, WithPriority(5))
+
+	// test
+	high, err := q.Enqueue(func() error { return nil }, WithPriority(1))
+	_, errLow := q.Enqueue(func() error { return nil }, WithPriority(9))
+
+	// assert
+	assert.NoError(t, err)
+	assert.ErrorIs(t, errLow, ErrQueueFull)
+	dropped, _ := q.FindWork(lowest)
+	assert.Equal(t, CANCELLED.String(), dropped.State())
+	assert.ErrorIs(t, dropped.Err(), ErrWorkDropped)
+	queued, _ := q.FindWork(high)
+	assert.Equal(t, IN_QUEUE.String(), queued.State())
+	assert.Equal(t, 3, q.Stats().Queued)
+}
+
+func TestQueue_WithOverflowPolicy_DropOldest(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(2), WithOverflowPolicy(DropOldest))
+	defer q.Stop()
+	q.Pause()
+	oldest, _ := q.Enqueue(func() error { return nil }, WithPriority(1))
+	_, _ = q.Enqueue(func() error { return nil }, WithPriority(2))
+
+	// test
+	newest, err := q.Enqueue(func() error { return nil }, WithPriority(9))
+
+	// assert
+	assert.NoError(t, err)
+	dropped, _ := q.FindWork(oldest)
+	assert.Equal(t, CANCELLED.String(), dropped.State())
+	assert.ErrorIs(t, dropped.Err(), ErrWorkDropped)
+	queued, _ := q.FindWork(newest)
+	assert.Equal(t, IN_QUEUE.String(), queued.State())
+}
+
+func TestQueue_WithSpillQueue_EnqueuesOnSecondaryQueue(t *testing.T) {
+	// setup
+	spill := NewQueue(WithWorkers(1))
+	defer spill.Stop()
+	q := fullQueue(t, []int{1}, WithSpillQueue(spill))
+	defer q.Stop()
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (string, error) {
+		return "spilled", nil
+	})
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	value, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "spilled", value)
+	_, onPrimary := q.FindWork(f.Id())
+	assert.False(t, onPrimary)
+	_, onSpill := spill.FindWork(f.Id())
+	assert.True(t, onSpill)
+}
+
+func TestQueue_WithSpillQueue_StoredWork_RemovedFromOriginStorage(t *testing.T) {
+	// setup
+	storage, err := NewFileStorage(filepath.Join(t.TempDir(), "queue.wal"))
+	assert.NoError(t, err)
+	defer storage.Close()
+	registry, values := recordingHandlers()
+	spill := NewQueue(WithWorkers(1))
+	defer spill.Stop()
+	q := fullQueue(t, []int{1}, WithSpillQueue(spill), WithStorage(storage), WithHandlers(registry))
+	defer q.Stop()
+
+	// test
+	id, err := q.EnqueueHandler(context.Background(), "record", testPayload{Value: 1})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		work, ok := spill.FindWork(id)
+		return ok && work.State() == COMPLETED.String()
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, []int{1}, values())
+	stored, _ := storage.Load()
+	assert.Empty(t, stored)
+}
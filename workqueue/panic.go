@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"fmt"
+
+	"github.com/rbell/toolchest/stacktrace"
+)
+
+// PanicError is the error of work that panicked, carrying the value passed to panic and the stack of the goroutine when it panicked
+type PanicError struct {
+	Value any
+	Stack stacktrace.StackTrace
+}
+
+// Error returns the value passed to panic
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("work panicked: %v", e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Format formats the error according to the fmt.Formatter interface.  %+v includes the stack when the work panicked.
+func (e *PanicError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		_, _ = fmt.Fprintf(s, "%v%+v", e.Error(), e.Stack)
+		return
+	}
+	_, _ = fmt.Fprint(s, e.Error())
+}
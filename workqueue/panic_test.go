@@ -0,0 +1,76 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_WorkPanics_ReportsPanicError(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	errCh := q.Errors()
+
+	// test
+	_, err := q.Enqueue(func() error {
+		panic("boom")
+	})
+	assert.NoError(t, err)
+
+	// assert
+	select {
+	case err := <-errCh:
+		var panicErr *PanicError
+		assert.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "boom", panicErr.Value)
+		assert.Equal(t, "work panicked: boom", panicErr.Error())
+		assert.True(t, panicErr.Stack.ReferencesFile("panic_test.go"))
+		assert.Contains(t, fmt.Sprintf("%+v", panicErr), "panic_test.go")
+	case <-time.After(time.Second):
+		assert.Fail(t, "panic was not reported")
+	}
+}
+
+func TestQueue_WorkPanics_WorkerSurvives(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	_, _ = q.Enqueue(func() error {
+		panic(errors.New("boom"))
+	})
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	value, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", value)
+}
+
+func TestPanicError_Unwrap_ReturnsErrorValue(t *testing.T) {
+	// setup
+	cause := errors.New("boom")
+
+	// test
+	err := &PanicError{Value: cause}
+
+	// assert
+	assert.ErrorIs(t, err, cause)
+	assert.Nil(t, (&PanicError{Value: "boom"}).Unwrap())
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// QueueState is the dispatching state of a queue
+type QueueState int
+
+// queue states
+const (
+	RUNNING QueueState = iota
+	PAUSED
+	STOPPED
+)
+
+func (qs QueueState) String() string {
+	switch qs {
+	case RUNNING:
+		return "Running"
+	case PAUSED:
+		return "Paused"
+	case STOPPED:
+		return "Stopped"
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the state as its name
+func (qs QueueState) MarshalText() ([]byte, error) {
+	return []byte(qs.String()), nil
+}
+
+// Status is a snapshot of whether a queue is dispatching work, the groups that are paused and the queue's stats
+type Status struct {
+	State        QueueState `json:"state"`
+	PausedGroups []string   `json:"pausedGroups"`
+	Stats        Stats      `json:"stats"`
+}
+
+// Pause stops work being dispatched to workers until the queue is resumed.  Work in process finishes, and work continues to be accepted
+// up to the queue's length while workers idle.  Work left on a paused queue is still performed when the queue is stopped or shut down, as
+// Shutdown resumes the queue and its groups.
+func (w *Queue) Pause() {
+	w.queueMux.Lock()
+	w.paused = true
+	// idle workers no longer take work from the queue, reducing the work it accepts
+	w.signalChanged()
+	w.queueMux.Unlock()
+	w.log(context.Background(), LogPaused, "queue paused")
+}
+
+// Resume resumes dispatching work to workers after the queue was paused
+func (w *Queue) Resume() {
+	w.queueMux.Lock()
+	w.paused = false
+	w.signalChanged()
+	w.queueMux.Unlock()
+	w.signalWake()
+	w.log(context.Background(), LogPaused, "queue resumed")
+}
+
+// resumeAll resumes dispatching work after the queue or any of its groups were paused
+func (w *Queue) resumeAll() {
+	w.queueMux.Lock()
+	w.paused = false
+	for _, g := range w.groups {
+		g.paused = false
+	}
+	w.signalChanged()
+	w.queueMux.Unlock()
+	w.signalWake()
+}
+
+// PauseGroup stops work enqueued in the group being dispatched until the group is resumed, while work in other groups continues to be
+// dispatched
+func (w *Queue) PauseGroup(name string) {
+	w.queueMux.Lock()
+	w.group(name).paused = true
+	w.queueMux.Unlock()
+	w.log(context.Background(), LogPaused, "group paused", slog.String("group", name))
+}
+
+// ResumeGroup resumes dispatching work enqueued in the group after the group was paused
+func (w *Queue) ResumeGroup(name string) {
+	w.queueMux.Lock()
+	if g, ok := w.groups[name]; ok {
+		g.paused = false
+	}
+	w.queueMux.Unlock()
+	w.signalWake()
+	w.log(context.Background(), LogPaused, "group resumed", slog.String("group", name))
+}
+
+// Status returns whether the queue is dispatching work, the groups that are paused and the queue's stats
+func (w *Queue) Status() Status {
+	status := Status{
+		State:        RUNNING,
+		PausedGroups: []string{},
+	}
+
+	w.queueMux.Lock()
+	if w.paused {
+		status.State = PAUSED
+	}
+	for name, g := range w.groups {
+		if g.paused {
+			status.PausedGroups = append(status.PausedGroups, name)
+		}
+	}
+	w.queueMux.Unlock()
+
+	if w.stopped.Load() {
+		status.State = STOPPED
+	}
+	sort.Strings(status.PausedGroups)
+	status.Stats = w.Stats()
+	return status
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_Pause_HoldsWorkUntilResumed(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2), WithQueueLength(3))
+	defer q.Stop()
+	performed := atomic.Int32{}
+	q.Pause()
+
+	// test
+	for i := 0; i < 3; i++ {
+		_, err := q.Enqueue(func() error {
+			performed.Add(1)
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+	time.Sleep(time.Millisecond * 20)
+
+	// assert
+	assert.Equal(t, int32(0), performed.Load())
+	status := q.Status()
+	assert.Equal(t, PAUSED, status.State)
+	assert.Equal(t, 3, status.Stats.Queued)
+	q.Resume()
+	assert.Eventually(t, func() bool {
+		return performed.Load() == 3
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, RUNNING, q.Status().State)
+}
+
+func TestQueue_Pause_AcceptsWorkUpToQueueLength(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2), WithQueueLength(2))
+	defer q.Stop()
+	q.Pause()
+	for i := 0; i < 2; i++ {
+		_, err := q.Enqueue(func() error { return nil })
+		assert.NoError(t, err)
+	}
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	_, err := q.EnqueueContext(ctx, func(ctx context.Context) error { return nil })
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueue_PauseGroup_HoldsOnlyGroupWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2))
+	defer q.Stop()
+	paused := atomic.Int32{}
+	running := atomic.Int32{}
+	q.PauseGroup("reports")
+
+	// test
+	_, _ = q.Enqueue(func() error {
+		paused.Add(1)
+		return nil
+	}, WithGroup("reports"))
+	_, _ = q.Enqueue(func() error {
+		running.Add(1)
+		return nil
+	}, WithGroup("emails"))
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return running.Load() == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(0), paused.Load())
+	status := q.Status()
+	assert.Equal(t, RUNNING, status.State)
+	assert.Equal(t, []string{"reports"}, status.PausedGroups)
+	q.ResumeGroup("reports")
+	assert.Eventually(t, func() bool {
+		return paused.Load() == 1
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, q.Status().PausedGroups)
+}
+
+func TestQueue_Shutdown_Paused_PerformsQueuedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(3))
+	performed := atomic.Int32{}
+	q.Pause()
+	q.PauseGroup("group")
+	for _, options := range [][]workOption{{}, {WithGroup("group")}} {
+		_, err := q.Enqueue(func() error {
+			performed.Add(1)
+			return nil
+		}, options...)
+		assert.NoError(t, err)
+	}
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), performed.Load())
+	assert.Empty(t, q.Status().PausedGroups)
+}
+
+func TestQueue_Status_Stopped(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	q.Pause()
+
+	// test
+	q.Stop()
+
+	// assert
+	assert.Equal(t, STOPPED, q.Status().State)
+	assert.Equal(t, "Stopped", q.Status().State.String())
+}
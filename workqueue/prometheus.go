@@ -0,0 +1,197 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBuckets are the upper bounds, in seconds, of the histogram buckets used by PrometheusMetrics by default
+var defaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// finishedStates are the final states work is counted in, in the order they are rendered
+var finishedStates = []workState{COMPLETED, FAILED, CANCELLED, SKIPPED}
+
+type PrometheusOption func(metrics *PrometheusMetrics)
+
+// PrometheusMetrics is Metrics accumulating counters and histograms of work moving through a queue, rendering them along with the queue's
+// gauges in the Prometheus text exposition format.  PrometheusMetrics is an http.Handler so it can be mounted as a metrics endpoint.
+type PrometheusMetrics struct {
+	namespace string
+	buckets   []float64
+	mux       *sync.Mutex
+	enqueued  uint64
+	started   uint64
+	finished  map[workState]uint64
+	waitTime  *histogram
+	runTime   *histogram
+	queue     *Queue
+}
+
+// NewPrometheusMetrics returns a reference to an initialized PrometheusMetrics prefixing metric names with the namespace
+func NewPrometheusMetrics(namespace string, options ...PrometheusOption) *PrometheusMetrics {
+	p := &PrometheusMetrics{
+		namespace: namespace,
+		buckets:   defaultBuckets,
+		mux:       &sync.Mutex{},
+		finished:  map[workState]uint64{},
+	}
+	for _, o := range options {
+		o(p)
+	}
+	p.waitTime = newHistogram(p.buckets)
+	p.runTime = newHistogram(p.buckets)
+	return p
+}
+
+// WithBuckets sets the upper bounds, in seconds, of the buckets of the wait and run time histograms
+func WithBuckets(buckets ...float64) PrometheusOption {
+	return func(metrics *PrometheusMetrics) {
+		metrics.buckets = buckets
+	}
+}
+
+// OnEnqueued counts enqueued work
+func (p *PrometheusMetrics) OnEnqueued(*QueuedWork) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.enqueued++
+}
+
+// OnStarted counts started attempts of work and observes how long the work waited
+func (p *PrometheusMetrics) OnStarted(_ *QueuedWork, waited time.Duration) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.started++
+	p.waitTime.observe(waited.Seconds())
+}
+
+// OnFinished counts finished work by state and observes how long the work ran
+func (p *PrometheusMetrics) OnFinished(work *QueuedWork, ran time.Duration) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	state := workState(work.state.Load())
+	p.finished[state]++
+	if !work.StartedAt().IsZero() {
+		p.runTime.observe(ran.Seconds())
+	}
+}
+
+func (p *PrometheusMetrics) observeQueue(queue *Queue) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	p.queue = queue
+}
+
+// WriteTo writes the metrics to writer in the Prometheus text exposition format
+func (p *PrometheusMetrics) WriteTo(writer io.Writer) (int64, error) {
+	p.mux.Lock()
+	queue := p.queue
+	buf := &bytes.Buffer{}
+	p.writeCounter(buf, "work_enqueued_total", "Work enqueued.", p.enqueued)
+	p.writeCounter(buf, "work_started_total", "Attempts of work started.", p.started)
+	p.writeHelp(buf, "work_finished_total", "Work finished by final state.", "counter")
+	for _, state := range finishedStates {
+		p.writeSample(buf, "work_finished_total", fmt.Sprintf(`state="%v"`, strings.ToLower(state.String())), strconv.FormatUint(p.finished[state], 10))
+	}
+	p.writeHistogram(buf, "work_wait_seconds", "Time work waited in the queue before starting.", p.waitTime)
+	p.writeHistogram(buf, "work_run_seconds", "Time attempts of work ran.", p.runTime)
+	p.mux.Unlock()
+
+	// gauges are read from the queue outside of the lock so rendering does not hold up work being observed
+	if queue != nil {
+		stats := queue.Stats()
+		p.writeGauge(buf, "workers", "Workers performing work.", stats.Workers)
+		p.writeGauge(buf, "busy_workers", "Workers busy performing work.", stats.BusyWorkers)
+		p.writeGauge(buf, "queued_work", "Work waiting in the queue.", stats.Queued)
+		p.writeGauge(buf, "queue_length", "Work the queue holds before enqueuing blocks.", stats.QueueLength)
+	}
+
+	return buf.WriteTo(writer)
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (p *PrometheusMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	//nolint:errcheck // nothing to do if the client went away
+	p.WriteTo(w)
+}
+
+func (p *PrometheusMetrics) name(name string) string {
+	if p.namespace == "" {
+		return name
+	}
+	return p.namespace + "_" + name
+}
+
+func (p *PrometheusMetrics) writeHelp(buf *bytes.Buffer, name, help, metricType string) {
+	fmt.Fprintf(buf, "# HELP %v %v\n# TYPE %v %v\n", p.name(name), help, p.name(name), metricType)
+}
+
+func (p *PrometheusMetrics) writeSample(buf *bytes.Buffer, name, labels, value string) {
+	if labels == "" {
+		fmt.Fprintf(buf, "%v %v\n", p.name(name), value)
+		return
+	}
+	fmt.Fprintf(buf, "%v{%v} %v\n", p.name(name), labels, value)
+}
+
+func (p *PrometheusMetrics) writeCounter(buf *bytes.Buffer, name, help string, value uint64) {
+	p.writeHelp(buf, name, help, "counter")
+	p.writeSample(buf, name, "", strconv.FormatUint(value, 10))
+}
+
+func (p *PrometheusMetrics) writeGauge(buf *bytes.Buffer, name, help string, value int) {
+	p.writeHelp(buf, name, help, "gauge")
+	p.writeSample(buf, name, "", strconv.Itoa(value))
+}
+
+func (p *PrometheusMetrics) writeHistogram(buf *bytes.Buffer, name, help string, h *histogram) {
+	p.writeHelp(buf, name, help, "histogram")
+	for i, bound := range h.bounds {
+		p.writeSample(buf, name+"_bucket", fmt.Sprintf(`le="%v"`, formatFloat(bound)), strconv.FormatUint(h.counts[i], 10))
+	}
+	p.writeSample(buf, name+"_bucket", `le="+Inf"`, strconv.FormatUint(h.count, 10))
+	p.writeSample(buf, name+"_sum", "", formatFloat(h.sum))
+	p.writeSample(buf, name+"_count", "", strconv.FormatUint(h.count, 10))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// histogram counts observations in cumulative buckets
+type histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)),
+	}
+}
+
+func (h *histogram) observe(value float64) {
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
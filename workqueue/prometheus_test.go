@@ -0,0 +1,104 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram_Observe_CountsCumulativeBuckets(t *testing.T) {
+	// setup
+	h := newHistogram([]float64{1, 2, 5})
+
+	// test
+	h.observe(0.5)
+	h.observe(2)
+	h.observe(10)
+
+	// assert
+	assert.Equal(t, []uint64{1, 2, 2}, h.counts)
+	assert.Equal(t, uint64(3), h.count)
+	assert.Equal(t, 12.5, h.sum)
+}
+
+func TestPrometheusMetrics_WriteTo_RendersMetrics(t *testing.T) {
+	// setup
+	metrics := NewPrometheusMetrics("test", WithBuckets(0.5, 1))
+	work := &QueuedWork{state: &atomic.Int32{}}
+	work.startedAt = time.Now()
+	work.state.Store(int32(COMPLETED))
+
+	metrics.OnEnqueued(work)
+	metrics.OnStarted(work, time.Millisecond*100)
+	metrics.OnFinished(work, time.Second*2)
+
+	// test
+	buf := &bytes.Buffer{}
+	_, err := metrics.WriteTo(buf)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP test_work_enqueued_total Work enqueued.
+# TYPE test_work_enqueued_total counter
+test_work_enqueued_total 1
+# HELP test_work_started_total Attempts of work started.
+# TYPE test_work_started_total counter
+test_work_started_total 1
+# HELP test_work_finished_total Work finished by final state.
+# TYPE test_work_finished_total counter
+test_work_finished_total{state="completed"} 1
+test_work_finished_total{state="failed"} 0
+test_work_finished_total{state="cancelled"} 0
+test_work_finished_total{state="skipped"} 0
+# HELP test_work_wait_seconds Time work waited in the queue before starting.
+# TYPE test_work_wait_seconds histogram
+test_work_wait_seconds_bucket{le="0.5"} 1
+test_work_wait_seconds_bucket{le="1"} 1
+test_work_wait_seconds_bucket{le="+Inf"} 1
+test_work_wait_seconds_sum 0.1
+test_work_wait_seconds_count 1
+# HELP test_work_run_seconds Time attempts of work ran.
+# TYPE test_work_run_seconds histogram
+test_work_run_seconds_bucket{le="0.5"} 0
+test_work_run_seconds_bucket{le="1"} 0
+test_work_run_seconds_bucket{le="+Inf"} 1
+test_work_run_seconds_sum 2
+test_work_run_seconds_count 1
+`, buf.String())
+}
+
+func TestPrometheusMetrics_ServeHTTP_RendersQueueGauges(t *testing.T) {
+	// setup
+	metrics := NewPrometheusMetrics("")
+	q := NewQueue(WithWorkers(3), WithMetrics(metrics))
+	_, _ = q.Enqueue(func() error { return errors.New("failed") })
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+	recorder := httptest.NewRecorder()
+
+	// test
+	metrics.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Type"), "text/plain")
+	body := recorder.Body.String()
+	assert.Contains(t, body, "work_finished_total{state=\"failed\"} 1\n")
+	assert.Contains(t, body, "# TYPE workers gauge\nworkers 3\n")
+	assert.Contains(t, body, "busy_workers 0\n")
+	assert.Contains(t, body, "queued_work 0\n")
+}
@@ -9,30 +9,97 @@ package workqueue
 import (
 	"container/heap"
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"runtime"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/rbell/toolchest/stacktrace"
 )
 
 type workOption func(item *workItem)
 
+// defaultHistorySize is the number of finished work items retained in a queue's history by default
+const defaultHistorySize = 100
+
+// ErrQueueStopped is returned when work is enqueued on a queue that has been stopped, and is the error of work skipped because the queue stopped
+var ErrQueueStopped = errors.New("queue stopped")
+
+// ShutdownError is returned by Shutdown when the context expires before all work has finished
+type ShutdownError struct {
+	// Dropped is the work that was queued or waiting to be retried and will not be performed
+	Dropped []*QueuedWork
+	err     error
+}
+
+func (e *ShutdownError) Error() string {
+	return fmt.Sprintf("queue shutdown incomplete, %v work items dropped: %v", len(e.Dropped), e.err)
+}
+
+func (e *ShutdownError) Unwrap() error {
+	return e.err
+}
+
 // Queue allow work to be queued up and worked on in a set number of go routines
 type Queue struct {
 	workerCount      int
+	workers          int
+	workerCh         chan *workItem
+	workersClosed    bool
+	resized          chan struct{}
+	autoscaler       *autoscaler
 	queueLength      *atomic.Int32
-	workChan         chan *workItem
-	workQueue        *workHeap
-	errChan          chan error
+	workQueue        scheduler
+	agingInterval    time.Duration
+	tenantWeights    map[string]int
+	scheduled        *workHeap
+	schedules        *sync.Map
+	dependents       map[uuid.UUID][]*workItem
+	uniqueKeys       map[string]*workItem
+	duplicatePolicy  DuplicatePolicy
+	paused           bool
+	overflowPolicy   OverflowPolicy
+	spill            *Queue
+	interceptor      Interceptor
+	broker           Broker
+	visibility       time.Duration
+	maxDeliveries    int
+	redeliveryDelay  Backoff
+	deadLetterBroker Broker
+	clock            Clock
+	manual           bool
+	steps            []chan step
+	limiter          *tokenBucket
+	groups           map[string]*workGroup
+	queueMux         *sync.Mutex
+	busy             int
+	active           int
+	wake             chan struct{}
+	changed          chan struct{}
+	workersWg        *sync.WaitGroup
+	done             chan struct{}
+	errChan          chan *ErrorEvent
 	errSubScriberMux *sync.Mutex
-	errorSubscribers []chan error
+	errorSubscribers map[uint64]*errorSubscriber
+	errSubscriberSeq uint64
+	errorsClosed     bool
 	stopped          atomic.Bool
-	breaked          bool
 	workItems        *sync.Map
 	queueContext     context.Context
 	queueCancel      context.CancelFunc
+	retryPolicy      *RetryPolicy
+	historySize      int
+	history          *workHistory
+	logger           LogPublisher
+	logLevels        map[LogEvent]slog.Level
+	metrics          Metrics
+	stats            *queueStats
+	storage          Storage
+	handlers         *HandlerRegistry
 }
 
 // NewQueue returns a reference to an initialized Queue
@@ -40,12 +107,30 @@ func NewQueue(options ...WorkQueueOption) *Queue {
 	wq := &Queue{
 		workerCount:      runtime.NumCPU(),
 		queueLength:      &atomic.Int32{},
-		errChan:          make(chan error),
+		scheduled:        newScheduleHeap(),
+		schedules:        &sync.Map{},
+		dependents:       map[uuid.UUID][]*workItem{},
+		uniqueKeys:       map[string]*workItem{},
+		groups:           map[string]*workGroup{},
+		queueMux:         &sync.Mutex{},
+		wake:             make(chan struct{}, 1),
+		changed:          make(chan struct{}),
+		workerCh:         make(chan *workItem),
+		resized:          make(chan struct{}),
+		workersWg:        &sync.WaitGroup{},
+		done:             make(chan struct{}),
+		errChan:          make(chan *ErrorEvent),
 		errSubScriberMux: &sync.Mutex{},
-		errorSubscribers: []chan error{},
+		errorSubscribers: map[uint64]*errorSubscriber{},
 		stopped:          atomic.Bool{},
-		breaked:          false,
 		workItems:        &sync.Map{},
+		historySize:      defaultHistorySize,
+		logLevels:        defaultLogLevels(),
+		stats:            &queueStats{},
+		clock:            realClock{},
+		visibility:       defaultVisibilityTimeout,
+		maxDeliveries:    defaultMaxDeliveries,
+		redeliveryDelay:  defaultRedeliveryBackoff,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -57,46 +142,231 @@ func NewQueue(options ...WorkQueueOption) *Queue {
 		o(wq)
 	}
 
-	wq.workChan = make(chan *workItem)
-	wq.workQueue = newWorkHeap(int(wq.queueLength.Load()))
+	wq.workQueue = wq.newScheduler()
+	wq.history = newWorkHistory(wq.historySize)
+	if wq.autoscaler != nil {
+		wq.workerCount = wq.autoscaler.clamp(wq.workerCount)
+	}
+	wq.SetWorkers(wq.workerCount)
+	wq.replay()
 
 	go wq.start()
+	if wq.autoscaler != nil {
+		go wq.autoscaler.run(wq)
+	}
+	if wq.broker != nil {
+		go wq.consume()
+	}
 
 	return wq
 }
 
-// Enqueue queues work to do on the workChan to be processed
-func (w *Queue) Enqueue(workToDo Work, options ...workOption) uuid.UUID {
-	wi := &workItem{
-		QueuedWork: &QueuedWork{
-			id:       uuid.New(),
-			priority: 1,
-			state:    &atomic.Int32{},
-		},
+// Enqueue queues work to be processed.  If the queue is full, Enqueue blocks until there is room in the queue.  If the queue has been
+// stopped ErrQueueStopped is returned.
+func (w *Queue) Enqueue(workToDo Work, options ...workOption) (uuid.UUID, error) {
+	return w.EnqueueContext(context.Background(), func(context.Context) error {
+		return workToDo()
+	}, options...)
+}
+
+// EnqueueContext queues context aware work to be processed.  The context passed to the work is derived from ctx and is cancelled when
+// the work is cancelled, the work's timeout or deadline passes, or the queue is stopped.  If the queue is full, EnqueueContext blocks until
+// there is room in the queue or ctx is done.  Delayed work, and work waiting on work it depends on, does not wait for room in the queue.  If
+// the queue has been stopped ErrQueueStopped is returned.
+func (w *Queue) EnqueueContext(ctx context.Context, workToDo ContextWork, options ...workOption) (uuid.UUID, error) {
+	return w.enqueue(ctx, w.newWorkItem(uuid.New(), workToDo, options...))
+}
 
-		workToDo: workToDo,
+// newWorkItem returns a work item for the work, timed and delayed by the queue's clock
+func (w *Queue) newWorkItem(id uuid.UUID, workToDo ContextWork, options ...workOption) *workItem {
+	wi := newWorkItem(id, workToDo, options...)
+	wi.clock = w.clock
+	if wi.delay > 0 {
+		wi.runAt = w.clock.Now().Add(wi.delay)
 	}
-	for _, option := range options {
-		option(wi)
+	return wi
+}
+
+// enqueue queues the work item, blocking while the queue is full unless the queue's overflow policy does not block
+func (w *Queue) enqueue(ctx context.Context, wi *workItem) (uuid.UUID, error) {
+	ids, err := w.enqueueItems(ctx, []*workItem{wi}, true)
+	if err != nil {
+		return uuid.Nil, err
 	}
+	return ids[0], nil
+}
 
-	w.workItems.Store(wi.id, wi)
+// enqueueItems queues the work items in order, holding the lock for as many items as the queue has room for.  While the queue is full the
+// queue's overflow policy is applied, where blocking is only allowed if block is true, otherwise ErrQueueFull is returned.  Returns the ids
+// of the work queued, or of the existing work duplicates were coalesced into or spilled to, before any error.
+func (w *Queue) enqueueItems(ctx context.Context, items []*workItem, block bool) ([]uuid.UUID, error) {
+	type enqueued struct {
+		wi     *workItem
+		queued int
+	}
+	ids := make([]uuid.UUID, 0, len(items))
+	work := []enqueued{}
+	replaced := []*workItem{}
+	dropped := []*workItem{}
+	// work is reported as enqueued, and the work it replaced or dropped finished, outside the lock
+	report := func() {
+		for _, r := range replaced {
+			w.finishWork(r, CANCELLED, ErrWorkReplaced)
+		}
+		for _, d := range dropped {
+			w.logWork(ctx, LogBackpressure, "queue full, work dropped", d, slog.Int("queueLength", int(w.queueLength.Load())))
+			w.finishWork(d, CANCELLED, ErrWorkDropped)
+		}
+		for _, e := range work {
+			w.onEnqueued(e.wi)
+			w.logWork(ctx, LogEnqueued, "work enqueued", e.wi, slog.Int("queued", e.queued))
+		}
+		work, replaced, dropped = work[:0], replaced[:0], dropped[:0]
+	}
 
-	if !w.stopped.Load() {
-		w.workChan <- wi
+	var err error
+	waiting := false
+	w.queueMux.Lock()
+items:
+	for len(ids) < len(items) {
+		wi := items[len(ids)]
+		if w.stopped.Load() {
+			err = ErrQueueStopped
+			break
+		}
+		if existing, ok := w.duplicate(wi); ok && w.duplicatePolicy != ReplaceDuplicates {
+			if w.duplicatePolicy == DropDuplicates {
+				err = ErrDuplicateWork
+				break
+			}
+			coalesce(existing, wi)
+			ids = append(ids, existing.id)
+			continue
+		}
+
+		// delayed work, and work waiting on work it depends on, does not wait for room in the queue
+		delayed := wi.runAt.After(w.clock.Now()) || len(wi.dependsOn) > 0
+		policy := w.overflowPolicy
+		if policy == BlockWhenFull && !block {
+			policy = RejectWhenFull
+		}
+		switch {
+		case delayed || !w.full() || policy == BlockWhenFull:
+		case policy == SpillWhenFull && w.spill != nil:
+			w.queueMux.Unlock()
+			report()
+			spilled, spillErr := w.spill.enqueueItems(ctx, []*workItem{wi}, block)
+			ids = append(ids, spilled...)
+			if spillErr != nil {
+				return ids, spillErr
+			}
+			w.queueMux.Lock()
+			continue
+		case policy == DropLowestPriority || policy == DropOldest:
+			victim := w.overflowVictim(wi, policy)
+			if victim == nil {
+				err = ErrQueueFull
+				break items
+			}
+			w.cancelPending(victim)
+			dropped = append(dropped, victim)
+		default:
+			err = ErrQueueFull
+			break items
+		}
+
+		if !delayed && w.full() {
+			changed := w.changed
+			w.queueMux.Unlock()
+			report()
+
+			// queue is full, block and wait for worker to take work off of queue
+			if !waiting {
+				w.logWork(ctx, LogBackpressure, "queue full, waiting for free worker", wi, slog.Int("queueLength", int(w.queueLength.Load())))
+				waiting = true
+			}
+			select {
+			case <-changed:
+			case <-ctx.Done():
+				return ids, ctx.Err()
+			}
+			w.queueMux.Lock()
+			continue
+		}
+
+		if err = w.holdForDependencies(wi); err != nil {
+			break
+		}
+		if existing, ok := w.duplicate(wi); ok && w.cancelPending(existing) {
+			replaced = append(replaced, existing)
+		}
+		if wi.uniqueKey != "" {
+			w.uniqueKeys[wi.uniqueKey] = wi
+		}
+		work = append(work, enqueued{wi: wi, queued: w.queueWork(ctx, wi)})
+		ids = append(ids, wi.id)
+	}
+	w.queueMux.Unlock()
+	report()
+
+	return ids, err
+}
+
+// queueWork tracks new work and pushes it onto the prioritized queue, the schedule if the work is delayed, or holds it if it is waiting on
+// work it depends on, returning the number of work items on the prioritized queue.  queueMux must be held.
+func (w *Queue) queueWork(ctx context.Context, wi *workItem) int {
+	// work may be enqueued waiting on one context while its own context is derived from another
+	if wi.parentCtx != nil {
+		ctx = wi.parentCtx
+	}
+	wi.ctx, wi.cancel = context.WithCancel(ctx)
+	if wi.enqueuedAt.IsZero() {
+		wi.enqueuedAt = w.clock.Now()
 	}
+	w.workItems.Store(wi.id, wi)
+	w.active++
+	if wi.waitingOn > 0 {
+		wi.state.Store(int32(WAITING))
+	} else {
+		w.pushOrSchedule(wi)
+	}
+	return w.workQueue.Len()
+}
 
-	return wi.id
+// pushOrSchedule pushes work onto the prioritized queue, or onto the schedule if the work is delayed.  queueMux must be held.
+func (w *Queue) pushOrSchedule(wi *workItem) {
+	wi.queuedAt = w.clock.Now()
+	if wi.runAt.After(wi.queuedAt) {
+		wi.state.Store(int32(SCHEDULED))
+		heap.Push(w.scheduled, wi)
+		w.signalWake()
+	} else {
+		w.pushWork(wi)
+	}
 }
 
-// Dequeue removes from the queue the work item with the specified id.  If the work item is in process, then an error is returned.
+// Dequeue removes from the queue the work item with the specified id.  If the id is of a recurring schedule, future occurrences of the
+// schedule are cancelled.  If the work item is in process, then an error is returned.
 func (w *Queue) Dequeue(id uuid.UUID) error {
+	if s, ok := w.schedules.LoadAndDelete(id); ok {
+		w.cancelSchedule(s.(*schedule))
+		return nil
+	}
+
 	if i, ok := w.workItems.Load(id); ok {
 		wi := i.(*workItem)
-		if wi.state.Load() == int32(IN_QUEUE) {
-			w.workQueue.Remove(wi.position)
-			w.workQueue.AdjustPriorities()
-			w.workItems.Delete(wi.id)
+		if wi.state.CompareAndSwap(int32(WAITING), int32(CANCELLED)) {
+			w.finishWork(wi, CANCELLED, context.Canceled)
+		} else if wi.state.CompareAndSwap(int32(SCHEDULED), int32(CANCELLED)) {
+			w.queueMux.Lock()
+			w.scheduled.Remove(wi.position)
+			w.queueMux.Unlock()
+			w.finishWork(wi, CANCELLED, context.Canceled)
+		} else if wi.state.CompareAndSwap(int32(IN_QUEUE), int32(CANCELLED)) || wi.state.CompareAndSwap(int32(RETRYING), int32(CANCELLED)) {
+			w.queueMux.Lock()
+			w.workQueue.Remove(wi)
+			w.queueMux.Unlock()
+			w.finishWork(wi, CANCELLED, context.Canceled)
 		} else if wi.state.Load() == int32(IN_PROGRESS) {
 			return fmt.Errorf("cannot delete work item %v because it is in process", id.String())
 		}
@@ -104,13 +374,28 @@ func (w *Queue) Dequeue(id uuid.UUID) error {
 	return nil
 }
 
+// Cancel cancels the work item with the specified id.  If the work item is queued it is removed from the queue, if it is in process the
+// context passed to the work is cancelled.
+func (w *Queue) Cancel(id uuid.UUID) {
+	if i, ok := w.workItems.Load(id); ok {
+		// Dequeue fails if the work is in process, in which case the work's context is cancelled
+		if err := w.Dequeue(id); err != nil {
+			i.(*workItem).cancel()
+		}
+	}
+}
+
 // SetPriority changes the priority of the queued work item with the uuid.
 func (w *Queue) SetPriority(id uuid.UUID, priority int) error {
 	if i, ok := w.workItems.Load(id); ok {
 		wi := i.(*workItem)
-		if wi.state.Load() == int32(IN_QUEUE) {
-			wi.priority = priority
-			w.workQueue.AdjustPriorities()
+		if state := wi.state.Load(); state == int32(IN_QUEUE) || state == int32(RETRYING) || state == int32(SCHEDULED) || state == int32(WAITING) {
+			w.queueMux.Lock()
+			defer w.queueMux.Unlock()
+			wi.setPriority(priority)
+			if state == int32(IN_QUEUE) {
+				w.workQueue.Fix(wi)
+			}
 		} else if wi.state.Load() == int32(IN_PROGRESS) {
 			return fmt.Errorf("cannot adjust prioroty on work item %v because it is in process", id.String())
 		}
@@ -128,126 +413,483 @@ func (w *Queue) WorkItems() []*QueuedWork {
 	return result
 }
 
-// Errors allows monitoring errors that occur on work submitted to queue
-func (w *Queue) Errors() chan error {
-	w.errSubScriberMux.Lock()
-	defer w.errSubScriberMux.Unlock()
+// History returns the most recently finished work items, ordered from oldest to newest, that satisfy all of the filters
+func (w *Queue) History(filters ...func(*QueuedWork) bool) []*QueuedWork {
+	return w.history.Items(filters...)
+}
+
+// FindWork returns the queued, in process or recently finished work item with the id
+func (w *Queue) FindWork(id uuid.UUID) (*QueuedWork, bool) {
+	if i, ok := w.workItems.Load(id); ok {
+		return i.(*workItem).QueuedWork, true
+	}
+	return w.history.Find(id)
+}
 
-	ch := make(chan error)
-	w.errorSubscribers = append(w.errorSubscribers, ch)
+// Errors allows monitoring errors that occur on work submitted to queue.  Each error is an *ErrorEvent identifying the work that failed.
+// Errors are delivered without blocking the queue, so errors arriving while the channel's buffer is full are dropped.  The channel is closed
+// once the queue has stopped and all work has finished.
+func (w *Queue) Errors() chan error {
+	ch := make(chan error, defaultErrorBuffer)
+	w.subscribeErrors(&errorSubscriber{
+		deliver: func(e *ErrorEvent) bool {
+			select {
+			case ch <- e:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() {
+			close(ch)
+		},
+		dropped: &atomic.Int64{},
+	})
 	return ch
 }
 
-// Stop stops the queue from accepting work
+// Stop stops the queue from accepting work and cancels the context passed to context aware work.  Work remaining in the queue is still
+// performed.
 func (w *Queue) Stop() {
 	w.stopped.Store(true)
+	w.cancelSchedules()
 	w.queueCancel()
+	w.notifyChanged()
 }
 
 // Break stops the queue form accepting any work and any work in queue is skipped
 func (w *Queue) Break() {
-	w.breaked = true
+	w.stopped.Store(true)
+	w.abandon()
 	w.Stop()
 }
 
+// Shutdown stops the queue from accepting work and waits for all queued, delayed, retrying and in process work to finish.  Future
+// occurrences of recurring schedules are cancelled.  Once all work has finished the queue's error subscriber channels are closed.  If ctx is
+// done before all work has finished, work that has not started is skipped, the context passed to context aware work in process is cancelled
+// and a ShutdownError reporting the dropped work is returned.  A paused queue and its paused groups are resumed so queued work is performed.
+func (w *Queue) Shutdown(ctx context.Context) error {
+	w.stopped.Store(true)
+	w.cancelSchedules()
+	w.resumeAll()
+	w.notifyChanged()
+
+	for {
+		w.queueMux.Lock()
+		active := w.active
+		changed := w.changed
+		w.queueMux.Unlock()
+		if active == 0 {
+			break
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			dropped := w.abandon()
+			w.queueCancel()
+			return &ShutdownError{Dropped: dropped, err: ctx.Err()}
+		}
+	}
+
+	w.queueCancel()
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return &ShutdownError{Dropped: []*QueuedWork{}, err: ctx.Err()}
+	}
+}
+
 // ResizeQueueLength adjusts the size of the queue
 func (w *Queue) ResizeQueueLength(length int) {
 	w.queueLength.Store(int32(length))
+	w.notifyChanged()
+}
+
+// SetWorkers adjusts the number of go routines working on the queue.  When growing, workers are started immediately.  When shrinking,
+// workers in process finish their current work before exiting.  The queue always has at least one worker.
+func (w *Queue) SetWorkers(workerCount int) {
+	workerCount = max(workerCount, 1)
+
+	w.queueMux.Lock()
+	defer w.queueMux.Unlock()
+	w.workerCount = workerCount
+	for !w.workersClosed && w.workers < w.workerCount {
+		w.workers++
+		w.workersWg.Add(1)
+		go w.doWork()
+	}
+	close(w.resized)
+	w.resized = make(chan struct{})
+	w.signalChanged()
+	w.signalWake()
 }
 
 func (w *Queue) start() {
-	defer func() {
-		close(w.errChan)
-		close(w.workChan)
-		w.queueCancel()
-	}()
+	defer close(w.done)
 
-	heap.Init(w.workQueue)
+	errorsPublished := make(chan struct{})
+	go w.publishErrors(errorsPublished)
 
-	// monitor for errors on a go routine
-	go func() {
-		stop := false
-		for {
-			select {
-			case e := <-w.errChan:
-				for _, sub := range w.errorSubscribers {
-					sub <- e
-				}
-			case <-w.queueContext.Done():
-				stop = true
-			}
-			if stop {
+	// Process work, waking when the next delayed work is due
+	stopTimer := func() bool { return false }
+outsideFor:
+	for {
+		stopTimer()
+		if next := w.dispatch(); !next.IsZero() {
+			stopTimer = w.clock.AfterFunc(next.Sub(w.clock.Now()), w.signalWake)
+		}
+		select {
+		case <-w.wake:
+		case <-w.queueContext.Done():
+			stopTimer()
+			break outsideFor
+		}
+	}
+	w.queueMux.Lock()
+	w.closeSteps()
+	w.queueMux.Unlock()
+
+	// Delayed work that is not yet due is skipped
+	w.skipScheduled()
+
+	// Finish any work left on queue
+	for work := w.finalWork(); work != nil; work = w.finalWork() {
+		w.workerCh <- work
+	}
+	w.queueMux.Lock()
+	w.workersClosed = true
+	close(w.workerCh)
+	w.queueMux.Unlock()
+	w.workersWg.Wait()
+	close(w.errChan)
+	<-errorsPublished
+}
+
+// dispatch moves delayed work that is due onto the prioritized queue and sends work from the prioritized queue to idle workers, returning
+// when the next delayed work is due or rate limits allow work to start.  Work in groups at their limits is skipped, leaving it on the queue
+// while work in other groups is dispatched.  No work is dispatched while the queue is paused.  Work is handed to workers outside the lock
+// as workers retiring after the queue shrinks need the lock to exit.
+func (w *Queue) dispatch() time.Time {
+	w.queueMux.Lock()
+	now := w.clock.Now()
+	next := w.promoteScheduled(now)
+	work := []*workItem{}
+	for !w.paused && w.busy < w.workerCount && (!w.manual || len(w.steps) > 0) {
+		if w.limiter != nil {
+			ok, available := w.limiter.available(now)
+			if !ok {
+				next = earliest(next, available)
 				break
 			}
 		}
-	}()
+		wi := w.popWork(func(wi *workItem) bool {
+			ok, available := w.eligible(wi, now)
+			next = earliest(next, available)
+			return ok
+		})
+		if wi == nil {
+			break
+		}
+		w.workerStarted(wi)
+		if w.manual {
+			w.stepped(wi)
+		}
+		work = append(work, wi)
+	}
+	if w.manual {
+		// steps requested when no work is eligible return without performing work
+		w.closeSteps()
+	}
+	w.queueMux.Unlock()
 
-	workerSemaphore := make(chan bool, w.workerCount)
-	workerCh := make(chan *workItem, w.workerCount)
-	defer close(workerCh)
-	defer close(workerSemaphore)
-	for i := 0; i < w.workerCount; i++ {
-		go w.doWork(workerCh, workerSemaphore)
+	for _, wi := range work {
+		w.workerCh <- wi
 	}
+	return next
+}
 
-	// Process work
-outsideFor:
+// promoteScheduled moves delayed work that is due by now onto the prioritized queue, returning when the next delayed work is due or the zero
+// time if there is no delayed work.  queueMux must be held.
+func (w *Queue) promoteScheduled(now time.Time) time.Time {
+	for wi := w.scheduled.Peek(); wi != nil; wi = w.scheduled.Peek() {
+		if wi.runAt.After(now) {
+			return wi.runAt
+		}
+		heap.Pop(w.scheduled)
+		// delayed work dequeued or skipped is dropped
+		if wi.state.CompareAndSwap(int32(SCHEDULED), int32(IN_QUEUE)) {
+			wi.queuedAt = now
+			w.workQueue.Push(wi)
+			w.signalChanged()
+		}
+	}
+	return time.Time{}
+}
+
+// skipScheduled skips all delayed work once the queue has stopped
+func (w *Queue) skipScheduled() {
+	w.queueMux.Lock()
+	skipped := []*workItem{}
+	for w.scheduled.Len() > 0 {
+		wi := heap.Pop(w.scheduled).(*workItem)
+		if wi.state.CompareAndSwap(int32(SCHEDULED), int32(SKIPPED)) {
+			skipped = append(skipped, wi)
+		}
+	}
+	w.queueMux.Unlock()
+
+	for _, wi := range skipped {
+		w.finishWork(wi, SKIPPED, ErrQueueStopped)
+	}
+}
+
+// finalWork pops the next work left on the prioritized queue once the queue has stopped
+func (w *Queue) finalWork() *workItem {
+	w.queueMux.Lock()
+	defer w.queueMux.Unlock()
+	work := w.popWork(func(*workItem) bool { return true })
+	if work != nil {
+		w.workerStarted(work)
+	}
+	return work
+}
+
+// full returns true if the prioritized queue cannot accept more work.  Work waiting on the queue for an idle worker does not count against
+// the queue's length, unless the queue is paused.  queueMux must be held.
+func (w *Queue) full() bool {
+	idle := max(w.workerCount-w.busy, 0)
+	if w.paused {
+		idle = 0
+	}
+	return w.workQueue.Len() >= int(w.queueLength.Load())+idle
+}
+
+// pushWork pushes work onto the prioritized queue and wakes the dispatcher.  queueMux must be held.
+func (w *Queue) pushWork(work *workItem) {
+	w.workQueue.Push(work)
+	w.signalWake()
+}
+
+// popWork pops the next eligible work off the prioritized queue, returning nil if there is no eligible work.  queueMux must be held.
+func (w *Queue) popWork(eligible func(wi *workItem) bool) *workItem {
+	work := w.workQueue.Pop(eligible)
+	if work != nil {
+		w.signalChanged()
+	}
+	return work
+}
+
+// newScheduler returns the scheduler ordering work on the prioritized queue, as configured by the queue's options
+func (w *Queue) newScheduler() scheduler {
+	newPriorityScheduler := func(length int) scheduler {
+		if w.agingInterval > 0 {
+			return newPriorityScheduler(newAgingHeap(length, w.agingInterval))
+		}
+		return newPriorityScheduler(newWorkHeap(length))
+	}
+	// tenants' heaps grow with their work rather than each being allocated the queue's length
+	if w.tenantWeights != nil {
+		return newFairScheduler(w.tenantWeights, newPriorityScheduler)
+	}
+	return newPriorityScheduler(int(w.queueLength.Load()))
+}
+
+// signalWake wakes the dispatcher without blocking
+func (w *Queue) signalWake() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// signalChanged wakes everything waiting on a change in the queue's state.  queueMux must be held.
+func (w *Queue) signalChanged() {
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+func (w *Queue) notifyChanged() {
+	w.queueMux.Lock()
+	defer w.queueMux.Unlock()
+	w.signalChanged()
+}
+
+// abandon skips all work that has not started, returning the work skipped
+func (w *Queue) abandon() []*QueuedWork {
+	dropped := []*QueuedWork{}
+	w.workItems.Range(func(key, value any) bool {
+		wi := value.(*workItem)
+		if wi.state.CompareAndSwap(int32(IN_QUEUE), int32(SKIPPED)) || wi.state.CompareAndSwap(int32(RETRYING), int32(SKIPPED)) ||
+			wi.state.CompareAndSwap(int32(SCHEDULED), int32(SKIPPED)) || wi.state.CompareAndSwap(int32(WAITING), int32(SKIPPED)) {
+			w.finishWork(wi, SKIPPED, ErrQueueStopped)
+			dropped = append(dropped, wi.QueuedWork)
+		}
+		return true
+	})
+	return dropped
+}
+
+func (w *Queue) doWork() {
+	defer w.workersWg.Done()
 	for {
-	insideFor:
+		// workers beyond the worker count retire between work
+		w.queueMux.Lock()
+		if w.workers > w.workerCount {
+			w.workers--
+			w.queueMux.Unlock()
+			return
+		}
+		resized := w.resized
+		w.queueMux.Unlock()
+
+		var wi *workItem
 		select {
-		case work := <-w.workChan:
-			if work != nil {
-				// If queue is empty try to send directly to workers via workerCh
-				if w.workQueue.Len() == 0 {
-					select {
-					case workerCh <- work:
-						break insideFor
-					default:
-					}
-				}
-				// Workers are busy and placing the workToDo on the channel failed - queue it on prioritized queue
-				if w.workQueue.Len() < int(w.queueLength.Load()) {
-					heap.Push(w.workQueue, work)
-					//w.workQueue.AdjustPriorities()
-				} else {
-					// queue is full, block and wait for worker to finish a task then add work to queue
-					fmt.Println("Waiting for free worker")
-					<-workerSemaphore
-					w.workQueue.AdjustPriorities()
-					wtemp := heap.Pop(w.workQueue).(*workItem)
-					workerCh <- wtemp
-					w.workQueue.Push(work)
-				}
-				fmt.Printf("Queue Length %v\n", w.workQueue.Len())
+		case work, ok := <-w.workerCh:
+			if !ok {
+				w.queueMux.Lock()
+				w.workers--
+				w.queueMux.Unlock()
+				return
+			}
+			wi = work
+		case <-resized:
+			continue
+		}
+
+		// work dequeued or skipped after being dispatched is not performed
+		if wi.state.CompareAndSwap(int32(IN_QUEUE), int32(IN_PROGRESS)) {
+			if wi.uniqueKey != "" {
+				w.queueMux.Lock()
+				w.releaseUniqueKey(wi)
+				w.queueMux.Unlock()
 			}
-		case <-workerSemaphore:
-			// worker done, pop and start next work (if anything in queue)
-			if w.workQueue.Len() > 0 {
-				w.workQueue.AdjustPriorities()
-				wtemp := heap.Pop(w.workQueue).(*workItem)
-				workerCh <- wtemp
+			wi.attempts.Add(1)
+			wi.started()
+			waited := w.clock.Now().Sub(wi.queuedAt)
+			w.onStarted(wi, waited)
+			w.logWork(wi.ctx, LogDispatched, "work started", wi, slog.Duration("waited", waited))
+			err := w.runWork(wi)
+			w.onAttemptFinished(w.clock.Now().Sub(wi.StartedAt()))
+			retried := false
+			if err != nil {
+				retried, err = w.retry(wi, err)
+			}
+			if !retried {
+				switch {
+				case err == nil:
+					w.finishWork(wi, COMPLETED, nil)
+				case wi.ctx.Err() != nil:
+					w.finishWork(wi, CANCELLED, err)
+				default:
+					w.finishWork(wi, FAILED, err)
+				}
 			}
-		case <-w.queueContext.Done():
-			break outsideFor
 		}
-	}
 
-	// Finish any work left on queue
-	for _, work := range w.workQueue.items {
-		if !w.breaked {
-			workerCh <- work
+		w.queueMux.Lock()
+		w.workerFinished(wi)
+		if wi.stepDone != nil {
+			close(wi.stepDone)
+			wi.stepDone = nil
 		}
+		w.signalChanged()
+		w.queueMux.Unlock()
+		w.signalWake()
 	}
 }
 
-func (w *Queue) doWork(workCh chan *workItem, semaphore chan bool) {
-	for wi := range workCh {
-		wi.state.Store(int32(IN_PROGRESS))
-		err := wi.workToDo()
-		if err != nil {
-			w.errChan <- err
+// runWork performs the work through the queue's interceptors, recovering a panic in the work or an interceptor as a PanicError so the
+// worker survives it
+func (w *Queue) runWork(wi *workItem) (err error) {
+	ctx, cancel := wi.workContext(w.queueContext, w.clock)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: stacktrace.CaptureStackTrace()}
+		}
+	}()
+	return w.intercept(wi)(ctx, wi.QueuedWork)
+}
+
+// retry re-queues failed work after the backoff of its retry policy, returning whether the work was re-queued and if not, the error to report
+func (w *Queue) retry(wi *workItem, err error) (bool, error) {
+	policy := wi.retryPolicy
+	if policy == nil {
+		policy = w.retryPolicy
+	}
+	if policy == nil || wi.ctx.Err() != nil || w.queueContext.Err() != nil {
+		return false, err
+	}
+
+	retry, delay, priority, reportErr := policy.next(wi.Attempt(), wi.Priority(), err)
+	if !retry {
+		return false, reportErr
+	}
+
+	w.queueMux.Lock()
+	wi.setPriority(priority)
+	wi.state.Store(int32(RETRYING))
+	w.queueMux.Unlock()
+	w.stats.retried.Add(1)
+	w.logWork(wi.ctx, LogRetrying, "work failed, retrying", wi, slog.Duration("backoff", delay), slog.Any("error", err))
+	w.clock.AfterFunc(delay, func() {
+		// work dequeued or skipped while waiting to retry has already been finished
+		if !wi.state.CompareAndSwap(int32(RETRYING), int32(IN_QUEUE)) {
+			return
+		}
+
+		w.queueMux.Lock()
+		requeued := wi.ctx.Err() == nil && w.queueContext.Err() == nil
+		if requeued {
+			wi.queuedAt = w.clock.Now()
+			w.pushWork(wi)
+		}
+		w.queueMux.Unlock()
+
+		if !requeued && wi.state.CompareAndSwap(int32(IN_QUEUE), int32(CANCELLED)) {
+			w.finishWork(wi, CANCELLED, err)
 		}
-		w.workItems.Delete(wi.id)
-		semaphore <- true
+	})
+	return true, nil
+}
+
+// finishWork records the final state of the work item, moving it from the queue's work items to the queue's history, and reports the error
+// of failed work to error subscribers
+func (w *Queue) finishWork(wi *workItem, state workState, err error) {
+	if wi.uniqueKey != "" {
+		w.queueMux.Lock()
+		w.releaseUniqueKey(wi)
+		w.queueMux.Unlock()
+	}
+	wi.finished(state, err)
+	if state == FAILED {
+		w.logWork(wi.ctx, LogFailed, "work failed", wi, slog.Duration("runTime", wi.RunTime()), slog.Any("error", err))
+		w.errChan <- newErrorEvent(wi, err)
+	} else {
+		w.logWork(wi.ctx, LogCompleted, "work finished", wi, slog.String("state", state.String()), slog.Duration("runTime", wi.RunTime()))
+	}
+	// skipped work, and work cancelled because the queue stopped, is kept in storage to be replayed when the queue is next started
+	if state != SKIPPED && (state != CANCELLED || w.queueContext.Err() == nil) {
+		w.removeStored(wi)
+	}
+	// work is added to the history before it is removed from the work items so work depending on it always finds it
+	w.history.Add(wi.QueuedWork)
+	w.workItems.Delete(wi.id)
+	w.onFinished(wi, state)
+	wi.finish(err)
+
+	w.queueMux.Lock()
+	cancelled, skipped := w.releaseDependents(wi, state)
+	w.active--
+	w.signalChanged()
+	w.queueMux.Unlock()
+
+	for _, dependent := range cancelled {
+		w.finishWork(dependent, CANCELLED, fmt.Errorf("%w: %v %v", ErrDependencyFailed, wi.id, state))
+	}
+	for _, dependent := range skipped {
+		w.finishWork(dependent, SKIPPED, ErrQueueStopped)
 	}
 }
@@ -0,0 +1,298 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+// Package queueAdmin provides HTTP handlers for inspecting and controlling work queues, mountable on a server via the server
+// configuration builders.
+package queueAdmin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbell/toolchest/server/serverConfig"
+	"github.com/rbell/toolchest/workqueue"
+)
+
+// defaultPrefix is the path the admin routes are mounted under unless configured otherwise
+const defaultPrefix = "/workqueue"
+
+// defaultDrainTimeout is how long a drain waits for work to finish unless the request gives a timeout
+const defaultDrainTimeout = time.Second * 30
+
+// Route is an admin endpoint to be mounted on a server
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+}
+
+// Admin serves HTTP endpoints inspecting and controlling named queues.  Endpoints, relative to the admin's path prefix, are:
+//
+//	GET  /queues                                 status of each queue
+//	GET  /queues/{queue}/stats                   status and stats of the queue
+//	GET  /queues/{queue}/work                    work in the queue
+//	POST /queues/{queue}/work/{id}/dequeue       dequeue the work
+//	POST /queues/{queue}/work/{id}/priority      set the priority of the work to the priority in the body, e.g. {"priority": 1}
+//	POST /queues/{queue}/pause[?group=name]      pause the queue, or the group
+//	POST /queues/{queue}/resume[?group=name]     resume the queue, or the group
+//	POST /queues/{queue}/drain[?timeout=30s]     shut the queue down once queued and in process work has finished, within 30s by default
+type Admin struct {
+	prefix string
+	mux    *sync.RWMutex
+	queues map[string]*workqueue.Queue
+}
+
+// AdminOption configures an Admin
+type AdminOption func(*Admin)
+
+// WithQueue registers the queue with the admin under the name
+func WithQueue(name string, queue *workqueue.Queue) AdminOption {
+	return func(a *Admin) {
+		a.queues[name] = queue
+	}
+}
+
+// WithPathPrefix sets the path the admin routes are mounted under, which defaults to /workqueue
+func WithPathPrefix(prefix string) AdminOption {
+	return func(a *Admin) {
+		a.prefix = prefix
+	}
+}
+
+// NewAdmin returns a reference to an initialized Admin
+func NewAdmin(options ...AdminOption) *Admin {
+	a := &Admin{
+		prefix: defaultPrefix,
+		mux:    &sync.RWMutex{},
+		queues: map[string]*workqueue.Queue{},
+	}
+	for _, o := range options {
+		o(a)
+	}
+	return a
+}
+
+// AddQueue registers the queue with the admin under the name, replacing any queue previously registered with the name
+func (a *Admin) AddQueue(name string, queue *workqueue.Queue) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.queues[name] = queue
+}
+
+// RemoveQueue removes the queue registered with the name
+func (a *Admin) RemoveQueue(name string) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	delete(a.queues, name)
+}
+
+// Routes returns the admin's endpoints
+func (a *Admin) Routes() []Route {
+	queue := a.prefix + "/queues/{queue}"
+	return []Route{
+		{Method: http.MethodGet, Path: a.prefix + "/queues", Handler: a.listQueues},
+		{Method: http.MethodGet, Path: queue + "/stats", Handler: a.withQueue(a.stats)},
+		{Method: http.MethodGet, Path: queue + "/work", Handler: a.withQueue(a.listWork)},
+		{Method: http.MethodPost, Path: queue + "/work/{id}/dequeue", Handler: a.withQueue(a.dequeue)},
+		{Method: http.MethodPost, Path: queue + "/work/{id}/priority", Handler: a.withQueue(a.setPriority)},
+		{Method: http.MethodPost, Path: queue + "/pause", Handler: a.withQueue(a.pause)},
+		{Method: http.MethodPost, Path: queue + "/resume", Handler: a.withQueue(a.resume)},
+		{Method: http.MethodPost, Path: queue + "/drain", Handler: a.withQueue(a.drain)},
+	}
+}
+
+// AddRoutes mounts the admin's endpoints on the HTTP server being configured
+func (a *Admin) AddRoutes(builder *serverConfig.HttpServerConfigBuilder) *serverConfig.HttpServerConfigBuilder {
+	for _, r := range a.Routes() {
+		builder.AddRoute(r.Method, r.Path, r.Handler)
+	}
+	return builder
+}
+
+// AddHttpsRoutes mounts the admin's endpoints on the HTTPS server being configured
+func (a *Admin) AddHttpsRoutes(builder *serverConfig.HttpsServerConfigBuilder) *serverConfig.HttpsServerConfigBuilder {
+	for _, r := range a.Routes() {
+		builder.AddRoute(r.Method, r.Path, r.Handler)
+	}
+	return builder
+}
+
+// Handler returns a handler serving the admin's endpoints, for mounting on a server not configured with the server configuration builders
+func (a *Admin) Handler() http.Handler {
+	mux := http.NewServeMux()
+	for _, r := range a.Routes() {
+		mux.Handle(fmt.Sprintf("%s %s", r.Method, r.Path), r.Handler)
+	}
+	return mux
+}
+
+// queueHandler handles a request for a registered queue
+type queueHandler func(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue)
+
+// withQueue resolves the queue named in the request's path, responding with 404 if no queue is registered with the name
+func (a *Admin) withQueue(handler queueHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("queue")
+		a.mux.RLock()
+		queue, ok := a.queues[name]
+		a.mux.RUnlock()
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Errorf("queue %v not found", name))
+			return
+		}
+		handler(w, r, queue)
+	}
+}
+
+func (a *Admin) listQueues(w http.ResponseWriter, _ *http.Request) {
+	type queueStatus struct {
+		Name string `json:"name"`
+		workqueue.Status
+	}
+
+	a.mux.RLock()
+	result := make([]queueStatus, 0, len(a.queues))
+	for name, queue := range a.queues {
+		result = append(result, queueStatus{Name: name, Status: queue.Status()})
+	}
+	a.mux.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	writeJson(w, http.StatusOK, result)
+}
+
+func (a *Admin) stats(w http.ResponseWriter, _ *http.Request, queue *workqueue.Queue) {
+	writeJson(w, http.StatusOK, queue.Status())
+}
+
+func (a *Admin) listWork(w http.ResponseWriter, _ *http.Request, queue *workqueue.Queue) {
+	work := queue.WorkItems()
+	sort.Slice(work, func(i, j int) bool {
+		return work[i].EnqueuedAt().Before(work[j].EnqueuedAt())
+	})
+	writeJson(w, http.StatusOK, work)
+}
+
+func (a *Admin) dequeue(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) {
+	id, ok := workId(w, r, queue)
+	if !ok {
+		return
+	}
+	if err := queue.Dequeue(id); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) setPriority(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) {
+	id, ok := workId(w, r, queue)
+	if !ok {
+		return
+	}
+	var body struct {
+		Priority *int `json:"priority"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Priority == nil {
+		writeError(w, http.StatusBadRequest, errors.New(`body must be of the form {"priority": 1}`))
+		return
+	}
+	if err := queue.SetPriority(id, *body.Priority); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *Admin) pause(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) {
+	if group := r.URL.Query().Get("group"); group != "" {
+		queue.PauseGroup(group)
+	} else {
+		queue.Pause()
+	}
+	writeJson(w, http.StatusOK, queue.Status())
+}
+
+func (a *Admin) resume(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) {
+	if group := r.URL.Query().Get("group"); group != "" {
+		queue.ResumeGroup(group)
+	} else {
+		queue.Resume()
+	}
+	writeJson(w, http.StatusOK, queue.Status())
+}
+
+// drain shuts the queue down, waiting for queued and in process work to finish until the optional timeout, or the default drain timeout,
+// passes.  The drain is not cancelled with the request, so a client disconnecting does not drop work.  Work dropped because the queue did
+// not drain in time is returned.
+func (a *Admin) drain(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) {
+	timeout := defaultDrainTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		var err error
+		if timeout, err = time.ParseDuration(t); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid timeout %v: %w", t, err))
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), timeout)
+	defer cancel()
+
+	err := queue.Shutdown(ctx)
+	var shutdownErr *workqueue.ShutdownError
+	if errors.As(err, &shutdownErr) {
+		writeJson(w, http.StatusGatewayTimeout, struct {
+			Error   string                  `json:"error"`
+			Dropped []*workqueue.QueuedWork `json:"dropped"`
+		}{
+			Error:   err.Error(),
+			Dropped: shutdownErr.Dropped,
+		})
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJson(w, http.StatusOK, queue.Status())
+}
+
+// workId parses the id of the work in the request's path, responding with 400 if the id is not a uuid or 404 if the queue has no such work
+func workId(w http.ResponseWriter, r *http.Request, queue *workqueue.Queue) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid work id %v: %w", r.PathValue("id"), err))
+		return uuid.Nil, false
+	}
+	if _, ok := queue.FindWork(id); !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("work %v not found", id))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func writeJson(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	//nolint:errcheck // the response has started, so a failed write cannot be reported to the client
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJson(w, status, struct {
+		Error string `json:"error"`
+	}{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,260 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package queueAdmin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rbell/toolchest/server/serverConfig"
+	"github.com/rbell/toolchest/workqueue"
+	"github.com/stretchr/testify/assert"
+)
+
+func request(t *testing.T, handler http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return rec
+}
+
+// pausedQueue returns a queue that holds enqueued work until it is resumed
+func pausedQueue() *workqueue.Queue {
+	q := workqueue.NewQueue(workqueue.WithWorkers(1), workqueue.WithQueueLength(10))
+	q.Pause()
+	return q
+}
+
+func TestAdmin_ListWork_ReturnsQueuedWork(t *testing.T) {
+	// setup
+	q := pausedQueue()
+	defer q.Stop()
+	id, _ := q.Enqueue(func() error { return nil }, workqueue.WithName("report"), workqueue.WithPriority(2))
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodGet, "/workqueue/queues/reports/work", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var work []map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &work))
+	assert.Len(t, work, 1)
+	assert.Equal(t, id.String(), work[0]["id"])
+	assert.Equal(t, "report", work[0]["name"])
+	assert.Equal(t, float64(2), work[0]["priority"])
+	assert.Equal(t, "Queued", work[0]["state"])
+}
+
+func TestAdmin_Stats_ReturnsStatus(t *testing.T) {
+	// setup
+	q := pausedQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(func() error { return nil })
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodGet, "/workqueue/queues/reports/stats", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status struct {
+		State string          `json:"state"`
+		Stats workqueue.Stats `json:"stats"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, "Paused", status.State)
+	assert.Equal(t, 1, status.Stats.Queued)
+}
+
+func TestAdmin_ListQueues_ReturnsEachQueue(t *testing.T) {
+	// setup
+	reports := pausedQueue()
+	defer reports.Stop()
+	emails := workqueue.NewQueue(workqueue.WithWorkers(1))
+	defer emails.Stop()
+	admin := NewAdmin(WithQueue("reports", reports))
+	admin.AddQueue("emails", emails)
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodGet, "/workqueue/queues", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var queues []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &queues))
+	assert.Equal(t, []string{"emails", "reports"}, []string{queues[0].Name, queues[1].Name})
+	assert.Equal(t, []string{"Running", "Paused"}, []string{queues[0].State, queues[1].State})
+}
+
+func TestAdmin_UnknownQueue_ReturnsNotFound(t *testing.T) {
+	// setup
+	admin := NewAdmin()
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodGet, "/workqueue/queues/missing/work", "")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "queue missing not found")
+}
+
+func TestAdmin_Dequeue_RemovesWork(t *testing.T) {
+	// setup
+	q := pausedQueue()
+	defer q.Stop()
+	id, _ := q.Enqueue(func() error { return nil })
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/work/"+id.String()+"/dequeue", "")
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	work, _ := q.FindWork(id)
+	assert.Equal(t, "Cancelled", work.State())
+}
+
+func TestAdmin_Dequeue_InvalidOrUnknownId(t *testing.T) {
+	// setup
+	q := pausedQueue()
+	defer q.Stop()
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	invalid := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/work/abc/dequeue", "")
+	unknown := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/work/"+uuid.NewString()+"/dequeue", "")
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	assert.Equal(t, http.StatusNotFound, unknown.Code)
+}
+
+func TestAdmin_SetPriority_ReprioritizesWork(t *testing.T) {
+	// setup
+	q := pausedQueue()
+	defer q.Stop()
+	id, _ := q.Enqueue(func() error { return nil }, workqueue.WithPriority(5))
+	admin := NewAdmin(WithQueue("reports", q))
+	path := "/workqueue/queues/reports/work/" + id.String() + "/priority"
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodPost, path, `{"priority": 1}`)
+	invalid := request(t, admin.Handler(), http.MethodPost, path, `{}`)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, http.StatusBadRequest, invalid.Code)
+	work, _ := q.FindWork(id)
+	assert.Equal(t, 1, work.Priority())
+}
+
+func TestAdmin_PauseResume(t *testing.T) {
+	// setup
+	q := workqueue.NewQueue(workqueue.WithWorkers(1))
+	defer q.Stop()
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	paused := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/pause", "")
+	groupPaused := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/pause?group=daily", "")
+	pausedStatus := q.Status()
+	request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/resume", "")
+	request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/resume?group=daily", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, paused.Code)
+	assert.Equal(t, http.StatusOK, groupPaused.Code)
+	assert.Equal(t, workqueue.PAUSED, pausedStatus.State)
+	assert.Equal(t, []string{"daily"}, pausedStatus.PausedGroups)
+	assert.Equal(t, workqueue.RUNNING, q.Status().State)
+	assert.Empty(t, q.Status().PausedGroups)
+}
+
+func TestAdmin_Drain_ShutsQueueDown(t *testing.T) {
+	// setup
+	q := workqueue.NewQueue(workqueue.WithWorkers(1))
+	_, _ = q.Enqueue(func() error {
+		time.Sleep(time.Millisecond * 10)
+		return nil
+	})
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/drain?timeout=5s", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, workqueue.STOPPED, q.Status().State)
+	assert.Empty(t, q.WorkItems())
+}
+
+func TestAdmin_Drain_RequestCancelled_DrainsQueue(t *testing.T) {
+	// setup
+	q := workqueue.NewQueue(workqueue.WithWorkers(1), workqueue.WithQueueLength(10))
+	for i := 0; i < 2; i++ {
+		_, _ = q.Enqueue(func() error {
+			time.Sleep(time.Millisecond * 10)
+			return nil
+		})
+	}
+	admin := NewAdmin(WithQueue("reports", q))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec := httptest.NewRecorder()
+
+	// test
+	admin.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/workqueue/queues/reports/drain", nil).WithContext(ctx))
+
+	// assert
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint64(2), q.Stats().Completed)
+}
+
+func TestAdmin_Drain_Timeout_ReturnsDroppedWork(t *testing.T) {
+	// setup
+	q := workqueue.NewQueue(workqueue.WithWorkers(1), workqueue.WithQueueLength(10))
+	release := make(chan struct{})
+	defer close(release)
+	_, _ = q.Enqueue(func() error {
+		<-release
+		return nil
+	})
+	id, _ := q.Enqueue(func() error { return nil })
+	admin := NewAdmin(WithQueue("reports", q))
+
+	// test
+	rec := request(t, admin.Handler(), http.MethodPost, "/workqueue/queues/reports/drain?timeout=10ms", "")
+
+	// assert
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Contains(t, rec.Body.String(), id.String())
+}
+
+func TestAdmin_AddRoutes_MountsEachRoute(t *testing.T) {
+	// setup
+	admin := NewAdmin(WithPathPrefix("/admin"))
+	cfg := serverConfig.BuildServerConfig().WithHttpServiceConfig(admin.AddRoutes(serverConfig.BuildHttpServiceConfig())).Build()
+
+	// test
+	routes := cfg.GetHttpServerConfig().GetRoutes()
+
+	// assert
+	assert.Contains(t, routes[http.MethodGet], "/admin/queues")
+	assert.Contains(t, routes[http.MethodPost], "/admin/queues/{queue}/drain")
+	assert.Len(t, routes[http.MethodGet], 3)
+	assert.Len(t, routes[http.MethodPost], 5)
+}
@@ -7,10 +7,16 @@
 package workqueue
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestQueue_PerformsWork(t *testing.T) {
@@ -39,3 +45,294 @@ func TestQueue_PerformsWork(t *testing.T) {
 	// assert
 	wg.Wait()
 }
+
+func TestQueue_EnqueueContext_WithTimeout_CancelsContext(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	result := make(chan error, 1)
+
+	// test
+	q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		result <- ctx.Err()
+		return nil
+	}, WithTimeout(time.Millisecond*10))
+
+	// assert
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		assert.Fail(t, "work context was not cancelled by timeout")
+	}
+}
+
+func TestQueue_EnqueueContext_WithDeadline_CancelsContext(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	result := make(chan error, 1)
+
+	// test
+	q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		result <- ctx.Err()
+		return nil
+	}, WithDeadline(time.Now().Add(time.Millisecond*10)))
+
+	// assert
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		assert.Fail(t, "work context was not cancelled by deadline")
+	}
+}
+
+func TestQueue_Cancel_InProgress_CancelsContext(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	id, _ := q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		result <- ctx.Err()
+		return nil
+	})
+	<-started
+
+	// test
+	q.Cancel(id)
+
+	// assert
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		assert.Fail(t, "work context was not cancelled")
+	}
+}
+
+func TestQueue_Stop_CancelsContextOfWorkInProgress(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	started := make(chan struct{})
+	result := make(chan error, 1)
+	q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		result <- ctx.Err()
+		return nil
+	})
+	<-started
+
+	// test
+	q.Stop()
+
+	// assert
+	select {
+	case err := <-result:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		assert.Fail(t, "work context was not cancelled when queue stopped")
+	}
+}
+
+func TestQueue_Shutdown_WaitsForQueuedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2))
+	errCh := q.Errors()
+	count := atomic.Int32{}
+	for i := 0; i < 10; i++ {
+		_, err := q.Enqueue(func() error {
+			time.Sleep(time.Millisecond * 10)
+			count.Add(1)
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), count.Load())
+	assert.Empty(t, q.WorkItems())
+	_, ok := <-errCh
+	assert.False(t, ok, "error subscriber channel should be closed")
+	_, err = q.Enqueue(func() error { return nil })
+	assert.ErrorIs(t, err, ErrQueueStopped)
+}
+
+func TestQueue_Shutdown_ContextDone_ReportsDroppedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	release := make(chan struct{})
+	defer close(release)
+	_, _ = q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		<-release
+		return ctx.Err()
+	})
+	dropped, _ := q.Enqueue(func() error {
+		return nil
+	}, WithName("dropped"))
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	var shutdownErr *ShutdownError
+	assert.ErrorAs(t, err, &shutdownErr)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, shutdownErr.Dropped, 1)
+	assert.Equal(t, "dropped", shutdownErr.Dropped[0].Name())
+	work, _ := q.FindWork(dropped)
+	assert.Equal(t, SKIPPED.String(), work.State())
+	assert.ErrorIs(t, work.Err(), ErrQueueStopped)
+}
+
+func TestQueue_Enqueue_Stopped_ReturnsErrQueueStopped(t *testing.T) {
+	// setup
+	q := NewQueue()
+	q.Stop()
+
+	// test
+	id, err := q.Enqueue(func() error { return nil })
+
+	// assert
+	assert.ErrorIs(t, err, ErrQueueStopped)
+	assert.Equal(t, uuid.Nil, id)
+	assert.Empty(t, q.WorkItems())
+}
+
+func TestQueue_EnqueueContext_Full_ReturnsContextError(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	defer close(release)
+	for i := 0; i < 2; i++ {
+		_, err := q.Enqueue(func() error {
+			<-release
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	// test
+	_, err := q.EnqueueContext(ctx, func(ctx context.Context) error { return nil })
+
+	// assert
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQueue_Break_SkipsQueuedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	skipped := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		return true, nil
+	})
+
+	// test
+	q.Break()
+	close(release)
+
+	// assert
+	_, err := skipped.Wait(context.Background())
+	assert.True(t, errors.Is(err, ErrQueueStopped))
+	work, _ := q.FindWork(skipped.Id())
+	assert.Equal(t, SKIPPED.String(), work.State())
+}
+
+func TestQueue_SetWorkers_Grow_StartsQueuedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{}, 3)
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(func() error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	<-started
+
+	// test
+	q.SetWorkers(3)
+
+	// assert
+	for i := 0; i < 2; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			assert.Fail(t, "queued work not started by added workers")
+			return
+		}
+	}
+	assert.Equal(t, 3, q.Stats().BusyWorkers)
+}
+
+func TestQueue_SetWorkers_Shrink_LimitsConcurrency(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(4))
+	release := make(chan struct{})
+	started := make(chan struct{}, 4)
+	for i := 0; i < 4; i++ {
+		_, _ = q.Enqueue(func() error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	for i := 0; i < 4; i++ {
+		<-started
+	}
+
+	// test
+	q.SetWorkers(1)
+	close(release)
+	running := atomic.Int32{}
+	maxRunning := atomic.Int32{}
+	for i := 0; i < 10; i++ {
+		_, _ = q.Enqueue(func() error {
+			n := running.Add(1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), maxRunning.Load())
+	assert.Equal(t, 1, q.Stats().Workers)
+	assert.Len(t, q.History(InState(COMPLETED)), 14)
+}
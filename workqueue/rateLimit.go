@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"time"
+)
+
+// tokenBucket limits the rate work is started.  A token is taken each time work starts, and tokens are added at the rate per second up to
+// the burst.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perSecond float64, burst int) *tokenBucket {
+	b := float64(burst)
+	return &tokenBucket{
+		rate:   perSecond,
+		burst:  b,
+		tokens: b,
+	}
+}
+
+// available returns true if a token is available at now, otherwise the time the next token will be available
+func (b *tokenBucket) available(now time.Time) (bool, time.Time) {
+	if b.last.IsZero() {
+		// the bucket starts full when first used, by the queue's clock
+		b.last = now
+	}
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		return true, time.Time{}
+	}
+	return false, now.Add(time.Duration((1 - b.tokens) / b.rate * float64(time.Second)))
+}
+
+// validRateLimit returns true if work can be limited to starting at the rate perSecond in bursts of burst.  A rate that is not positive
+// would never make a token available.
+func validRateLimit(perSecond float64, burst int) bool {
+	return perSecond > 0 && burst >= 1
+}
+
+// take takes a token from the bucket
+func (b *tokenBucket) take() {
+	b.tokens--
+}
+
+// workGroup limits the concurrency and rate of the work enqueued in a group
+type workGroup struct {
+	maxConcurrency int
+	running        int
+	limiter        *tokenBucket
+	paused         bool
+}
+
+// group returns the group with the name, creating it if it does not exist
+func (w *Queue) group(name string) *workGroup {
+	g, ok := w.groups[name]
+	if !ok {
+		g = &workGroup{}
+		w.groups[name] = g
+	}
+	return g
+}
+
+// eligible returns true if work may be started now without exceeding the limits of the group it was enqueued in, otherwise the time the
+// group's rate limit allows work to start if the group is rate limited.  Work in a paused group is not eligible.  queueMux must be held.
+func (w *Queue) eligible(wi *workItem, now time.Time) (bool, time.Time) {
+	g, ok := w.groups[wi.group]
+	if !ok || wi.group == "" {
+		return true, time.Time{}
+	}
+	if g.paused {
+		// the group becomes eligible once it is resumed
+		return false, time.Time{}
+	}
+	if g.maxConcurrency > 0 && g.running >= g.maxConcurrency {
+		// the group becomes eligible once its running work finishes
+		return false, time.Time{}
+	}
+	if g.limiter != nil {
+		return g.limiter.available(now)
+	}
+	return true, time.Time{}
+}
+
+// workerStarted records work dispatched to a worker against the queue's and its group's limits.  queueMux must be held.
+func (w *Queue) workerStarted(wi *workItem) {
+	w.busy++
+	if w.limiter != nil {
+		w.limiter.take()
+	}
+	if g, ok := w.groups[wi.group]; ok && wi.group != "" {
+		g.running++
+		if g.limiter != nil {
+			g.limiter.take()
+		}
+	}
+}
+
+// workerFinished records a worker finishing work against the queue's and its group's limits.  queueMux must be held.
+func (w *Queue) workerFinished(wi *workItem) {
+	w.busy--
+	if g, ok := w.groups[wi.group]; ok && wi.group != "" {
+		g.running--
+	}
+}
+
+// earliest returns the earlier of two times, ignoring zero times
+func earliest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,156 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket_Available(t *testing.T) {
+	bucket := newTokenBucket(10, 2)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		ok, _ := bucket.available(now)
+		assert.True(t, ok)
+		bucket.take()
+	}
+	ok, next := bucket.available(now)
+	assert.False(t, ok)
+	assert.Equal(t, now.Add(time.Millisecond*100), next)
+
+	ok, _ = bucket.available(now.Add(time.Millisecond * 100))
+	assert.True(t, ok)
+	ok, _ = bucket.available(now.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, float64(2), bucket.tokens)
+}
+
+func TestQueue_WithGroupConcurrency_LimitsGroupAndServesOtherWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(4), WithGroupConcurrency("downstream", 1))
+	defer q.Stop()
+	release := make(chan struct{})
+	running := atomic.Int32{}
+	maxRunning := atomic.Int32{}
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(func() error {
+			n := running.Add(1)
+			if n > maxRunning.Load() {
+				maxRunning.Store(n)
+			}
+			<-release
+			running.Add(-1)
+			return nil
+		}, WithGroup("downstream"))
+	}
+
+	// test
+	other := EnqueueFunc(context.Background(), q, func(ctx context.Context) (bool, error) {
+		return true, nil
+	}, WithPriority(10))
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := other.Wait(ctx)
+	assert.NoError(t, err, "work outside the group was not served while the group was at its limit")
+	assert.Eventually(t, func() bool {
+		return running.Load() == 1
+	}, time.Second, time.Millisecond)
+	close(release)
+	assert.Eventually(t, func() bool {
+		return q.Stats().Completed == 4
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, int32(1), maxRunning.Load())
+}
+
+func TestQueue_WithRateLimit_LimitsRateWorkStarts(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(4), WithRateLimit(50, 1))
+	start := time.Now()
+
+	// test
+	for i := 0; i < 6; i++ {
+		_, _ = q.Enqueue(func() error { return nil })
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	// the first work starts immediately, the remaining five at 20ms intervals
+	assert.GreaterOrEqual(t, time.Since(start), time.Millisecond*100)
+}
+
+func TestQueue_WithRateLimit_InvalidLimit_Ignored(t *testing.T) {
+	tests := []struct {
+		name      string
+		perSecond float64
+		burst     int
+	}{
+		{"zero rate", 0, 1},
+		{"negative rate", -1, 1},
+		{"NaN rate", math.NaN(), 1},
+		{"zero burst", 10, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// setup
+			q := NewQueue(WithWorkers(1), WithRateLimit(test.perSecond, test.burst), WithGroupRateLimit("group", test.perSecond, test.burst))
+
+			// test
+			_, _ = q.Enqueue(func() error { return nil })
+			_, _ = q.Enqueue(func() error { return nil }, WithGroup("group"))
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			err := q.Shutdown(ctx)
+
+			// assert
+			assert.NoError(t, err)
+			assert.Nil(t, q.limiter)
+			assert.Nil(t, q.group("group").limiter)
+			assert.Equal(t, uint64(2), q.Stats().Completed)
+		})
+	}
+}
+
+func TestQueue_WithGroupRateLimit_LimitsGroupOnly(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(2), WithGroupRateLimit("limited", 1, 1))
+	defer q.Stop()
+	limited := atomic.Int32{}
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(func() error {
+			limited.Add(1)
+			return nil
+		}, WithGroup("limited"))
+	}
+
+	// test
+	unlimited := atomic.Int32{}
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(func() error {
+			unlimited.Add(1)
+			return nil
+		})
+	}
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return unlimited.Load() == 3
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, int32(1), limited.Load())
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrRetriesExhausted is wrapped by the error delivered to error subscribers when work has failed on every attempt allowed by its retry policy
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// Backoff returns the delay before the next attempt of work that failed on the specified attempt (the first attempt is 1)
+type Backoff func(attempt int) time.Duration
+
+type RetryOption func(policy *RetryPolicy)
+
+// RetryPolicy describes how work that returns an error is retried
+type RetryPolicy struct {
+	maxAttempts int
+	backoff     Backoff
+	retryable   func(error) bool
+	priority    func(priority, attempt int) int
+}
+
+// NewRetryPolicy returns a reference to a RetryPolicy allowing up to maxAttempts attempts (including the first) of failed work.  By default
+// every error is retried immediately at the work's original priority.
+func NewRetryPolicy(maxAttempts int, options ...RetryOption) *RetryPolicy {
+	p := &RetryPolicy{
+		maxAttempts: maxAttempts,
+		backoff:     ConstantBackoff(0),
+	}
+	for _, o := range options {
+		o(p)
+	}
+	return p
+}
+
+// WithBackoff sets the backoff used to delay retries
+func WithBackoff(backoff Backoff) RetryOption {
+	return func(policy *RetryPolicy) {
+		policy.backoff = backoff
+	}
+}
+
+// WithRetryable sets a predicate deciding whether an error is retried.  Errors the predicate returns false for are not retried.
+func WithRetryable(retryable func(error) bool) RetryOption {
+	return func(policy *RetryPolicy) {
+		policy.retryable = retryable
+	}
+}
+
+// WithRetryPriority sets a function returning the priority work is re-queued at given its current priority and the attempt that failed
+func WithRetryPriority(priority func(priority, attempt int) int) RetryOption {
+	return func(policy *RetryPolicy) {
+		policy.priority = priority
+	}
+}
+
+// ConstantBackoff waits the same delay before every retry
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff doubles the delay before each retry starting with initial, never exceeding maxDelay
+func ExponentialBackoff(initial, maxDelay time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < maxDelay; i++ {
+			delay *= 2
+		}
+		return min(delay, maxDelay)
+	}
+}
+
+// JitteredBackoff randomizes the delay of backoff between zero and the delay it returns, spreading out retries of work that failed together
+func JitteredBackoff(backoff Backoff) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+		if delay <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int64N(int64(delay)))
+	}
+}
+
+// next returns whether work failing with err on the attempt should be retried, the delay before retrying and the priority to re-queue it at.
+// If the work should not be retried, the error to report is returned.
+func (p *RetryPolicy) next(attempt, priority int, err error) (bool, time.Duration, int, error) {
+	if p.retryable != nil && !p.retryable(err) {
+		return false, 0, priority, err
+	}
+	if attempt >= p.maxAttempts {
+		return false, 0, priority, fmt.Errorf("%w after %v attempts: %w", ErrRetriesExhausted, attempt, err)
+	}
+	if p.priority != nil {
+		priority = p.priority(priority, attempt)
+	}
+	return true, p.backoff(attempt), priority, nil
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstantBackoff_ReturnsDelay(t *testing.T) {
+	backoff := ConstantBackoff(time.Second)
+
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, time.Second, backoff(5))
+}
+
+func TestExponentialBackoff_DoublesDelayUpToMax(t *testing.T) {
+	backoff := ExponentialBackoff(time.Millisecond*10, time.Millisecond*50)
+
+	assert.Equal(t, time.Millisecond*10, backoff(1))
+	assert.Equal(t, time.Millisecond*20, backoff(2))
+	assert.Equal(t, time.Millisecond*40, backoff(3))
+	assert.Equal(t, time.Millisecond*50, backoff(4))
+	assert.Equal(t, time.Millisecond*50, backoff(100))
+}
+
+func TestJitteredBackoff_ReturnsDelayWithinBackoff(t *testing.T) {
+	backoff := JitteredBackoff(ConstantBackoff(time.Millisecond * 10))
+
+	for i := 1; i < 100; i++ {
+		delay := backoff(i)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.Less(t, delay, time.Millisecond*10)
+	}
+	assert.Equal(t, time.Duration(0), JitteredBackoff(ConstantBackoff(0))(1))
+}
+
+func TestRetryPolicy_Next(t *testing.T) {
+	errTest := errors.New("test")
+	policy := NewRetryPolicy(3, WithBackoff(ConstantBackoff(time.Second)), WithRetryPriority(func(priority, attempt int) int {
+		return priority + attempt
+	}))
+
+	retry, delay, priority, err := policy.next(1, 5, errTest)
+	assert.True(t, retry)
+	assert.Equal(t, time.Second, delay)
+	assert.Equal(t, 6, priority)
+	assert.NoError(t, err)
+
+	retry, _, _, err = policy.next(3, 5, errTest)
+	assert.False(t, retry)
+	assert.ErrorIs(t, err, ErrRetriesExhausted)
+	assert.ErrorIs(t, err, errTest)
+}
+
+func TestRetryPolicy_Next_NotRetryable_ReturnsError(t *testing.T) {
+	errTest := errors.New("test")
+	policy := NewRetryPolicy(3, WithRetryable(func(err error) bool {
+		return false
+	}))
+
+	retry, _, _, err := policy.next(1, 1, errTest)
+
+	assert.False(t, retry)
+	assert.Equal(t, errTest, err)
+}
+
+func TestQueue_WithRetryPolicy_RetriesUntilSuccess(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	attempts := atomic.Int32{}
+	done := make(chan struct{})
+
+	// test
+	id, _ := q.Enqueue(func() error {
+		if attempts.Add(1) < 3 {
+			return errors.New("failed")
+		}
+		close(done)
+		return nil
+	}, WithRetryPolicy(NewRetryPolicy(5, WithBackoff(ConstantBackoff(time.Millisecond)))))
+
+	// assert
+	select {
+	case <-done:
+		assert.Equal(t, int32(3), attempts.Load())
+	case <-time.After(time.Second):
+		assert.Fail(t, "work was not retried", "id %v", id)
+	}
+}
+
+func TestQueue_WithDefaultRetryPolicy_ReportsExhaustedError(t *testing.T) {
+	// setup
+	errTest := errors.New("test")
+	q := NewQueue(WithWorkers(1), WithDefaultRetryPolicy(NewRetryPolicy(3, WithBackoff(ExponentialBackoff(time.Millisecond, time.Millisecond*5)))))
+	defer q.Stop()
+	errCh := q.Errors()
+	attempts := atomic.Int32{}
+
+	// test
+	q.Enqueue(func() error {
+		attempts.Add(1)
+		return errTest
+	})
+
+	// assert
+	select {
+	case err := <-errCh:
+		assert.ErrorIs(t, err, ErrRetriesExhausted)
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, int32(3), attempts.Load())
+	case <-time.After(time.Second):
+		assert.Fail(t, "exhausted error not reported")
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrNeverDue is returned when scheduling work on a cron expression that is never due
+var ErrNeverDue = errors.New("schedule is never due")
+
+// schedule enqueues an occurrence of work each time its cron schedule is due.  The next occurrence is enqueued once the previous occurrence
+// has finished, so occurrences never overlap.
+type schedule struct {
+	id        uuid.UUID
+	cron      *CronSchedule
+	workToDo  ContextWork
+	options   []workOption
+	mux       *sync.Mutex
+	pending   uuid.UUID
+	cancelled bool
+}
+
+// Schedule enqueues work each time the cron expression (see ParseCron) is due, returning the id of the schedule.  Each occurrence of the
+// work is enqueued as delayed work with an id of its own, once the previous occurrence has finished.  Dequeuing the schedule's id cancels
+// the pending occurrence and all future occurrences.  Schedules are cancelled when the queue is stopped.
+func (w *Queue) Schedule(spec string, workToDo ContextWork, options ...workOption) (uuid.UUID, error) {
+	cron, err := ParseCron(spec)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if w.stopped.Load() {
+		return uuid.Nil, ErrQueueStopped
+	}
+
+	s := &schedule{
+		id:       uuid.New(),
+		cron:     cron,
+		workToDo: workToDo,
+		options:  options,
+		mux:      &sync.Mutex{},
+	}
+	w.schedules.Store(s.id, s)
+	if err := w.scheduleNext(s, w.clock.Now()); err != nil {
+		w.schedules.Delete(s.id)
+		return uuid.Nil, err
+	}
+	return s.id, nil
+}
+
+// scheduleNext enqueues the next occurrence of the schedule due after the time
+func (w *Queue) scheduleNext(s *schedule, after time.Time) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.cancelled {
+		return nil
+	}
+
+	next := s.cron.Next(after)
+	if next.IsZero() {
+		return fmt.Errorf("%w: %v", ErrNeverDue, s.id)
+	}
+
+	options := append([]workOption{}, s.options...)
+	options = append(options, WithRunAt(next), func(item *workItem) {
+		item.onFinish = func(error) {
+			if w.stopped.Load() {
+				return
+			}
+			// an occurrence dequeued before it was due is skipped rather than enqueued again
+			after := w.clock.Now()
+			if after.Before(next) {
+				after = next
+			}
+			//nolint:errcheck // a schedule that is no longer due simply stops
+			w.scheduleNext(s, after)
+		}
+	})
+	id, err := w.enqueue(context.Background(), w.newWorkItem(uuid.New(), s.workToDo, options...))
+	if err != nil {
+		return err
+	}
+	s.pending = id
+	return nil
+}
+
+// cancelSchedule cancels future occurrences of the schedule, dequeuing its pending occurrence if it has not started
+func (w *Queue) cancelSchedule(s *schedule) {
+	s.mux.Lock()
+	s.cancelled = true
+	pending := s.pending
+	s.mux.Unlock()
+
+	//nolint:errcheck // an occurrence in process is left to finish
+	w.Dequeue(pending)
+}
+
+// cancelSchedules cancels all of the queue's schedules
+func (w *Queue) cancelSchedules() {
+	w.schedules.Range(func(key, value any) bool {
+		w.schedules.Delete(key)
+		w.cancelSchedule(value.(*schedule))
+		return true
+	})
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_WithDelay_RunsWorkOnceDue(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	mux := &sync.Mutex{}
+	order := []string{}
+	done := make(chan struct{})
+	record := func(name string) Work {
+		return func() error {
+			mux.Lock()
+			defer mux.Unlock()
+			order = append(order, name)
+			if len(order) == 2 {
+				close(done)
+			}
+			return nil
+		}
+	}
+
+	// test
+	enqueued := time.Now()
+	id, err := q.Enqueue(record("delayed"), WithDelay(time.Millisecond*50), WithPriority(0))
+	assert.NoError(t, err)
+	_, _ = q.Enqueue(record("immediate"), WithPriority(10))
+	work, _ := q.FindWork(id)
+	state := work.State()
+
+	// assert
+	assert.Equal(t, SCHEDULED.String(), state)
+	select {
+	case <-done:
+		assert.Equal(t, []string{"immediate", "delayed"}, order)
+		assert.GreaterOrEqual(t, work.StartedAt().Sub(enqueued), time.Millisecond*50)
+	case <-time.After(time.Second):
+		assert.Fail(t, "delayed work not performed")
+	}
+}
+
+func TestQueue_WithRunAt_PastTime_RunsImmediately(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+
+	// test
+	f := EnqueueFunc(context.Background(), q, func(ctx context.Context) (int, error) {
+		return 1, nil
+	}, WithRunAt(time.Now().Add(-time.Minute)))
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	value, err := f.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, value)
+}
+
+func TestQueue_Dequeue_DelayedWork_CancelsWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	performed := atomic.Bool{}
+	id, _ := q.Enqueue(func() error {
+		performed.Store(true)
+		return nil
+	}, WithDelay(time.Millisecond*20))
+
+	// test
+	err := q.Dequeue(id)
+
+	// assert
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond * 50)
+	assert.False(t, performed.Load())
+	work, _ := q.FindWork(id)
+	assert.Equal(t, CANCELLED.String(), work.State())
+	assert.Equal(t, 0, q.Stats().Scheduled)
+}
+
+func TestQueue_Shutdown_WaitsForDelayedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	performed := atomic.Bool{}
+	_, _ = q.Enqueue(func() error {
+		performed.Store(true)
+		return nil
+	}, WithDelay(time.Millisecond*20))
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.True(t, performed.Load())
+}
+
+func TestQueue_Stop_SkipsDelayedWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	id, _ := q.Enqueue(func() error { return nil }, WithDelay(time.Hour))
+
+	// test
+	q.Stop()
+
+	// assert
+	assert.Eventually(t, func() bool {
+		work, _ := q.FindWork(id)
+		return work.State() == SKIPPED.String()
+	}, time.Second, time.Millisecond*5)
+}
+
+func TestQueue_Schedule_EnqueuesOccurrencesUntilDequeued(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	defer q.Stop()
+	occurrences := atomic.Int32{}
+
+	// test
+	id, err := q.Schedule("@every 10ms", func(ctx context.Context) error {
+		occurrences.Add(1)
+		return nil
+	}, WithName("scheduled"))
+
+	// assert
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return occurrences.Load() >= 3
+	}, time.Second, time.Millisecond*5)
+
+	assert.NoError(t, q.Dequeue(id))
+	count := occurrences.Load()
+	time.Sleep(time.Millisecond * 50)
+	assert.LessOrEqual(t, occurrences.Load(), count+1)
+	assert.Eventually(t, func() bool {
+		return len(q.WorkItems()) == 0
+	}, time.Second, time.Millisecond*5)
+	assert.Equal(t, "scheduled", q.History()[0].Name())
+}
+
+func TestQueue_Schedule_InvalidExpression_ReturnsError(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+
+	// test
+	_, err := q.Schedule("not a schedule", func(ctx context.Context) error { return nil })
+	_, errNever := q.Schedule("0 0 31 2 *", func(ctx context.Context) error { return nil })
+
+	// assert
+	assert.Error(t, err)
+	assert.ErrorIs(t, errNever, ErrNeverDue)
+}
+
+func TestQueue_Shutdown_CancelsSchedules(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1))
+	_, _ = q.Schedule("@daily", func(ctx context.Context) error { return nil })
+
+	// test
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := q.Shutdown(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, CANCELLED.String(), q.History()[0].State())
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// scheduler orders the work waiting on a queue for a worker.  A scheduler is only used while holding the queue's lock.
+type scheduler interface {
+	// Len returns the number of work items waiting
+	Len() int
+	// Push adds the work item
+	Push(wi *workItem)
+	// Pop removes and returns the next work item to be performed for which eligible returns true, or nil if there is no eligible work
+	Pop(eligible func(wi *workItem) bool) *workItem
+	// Remove removes the work item
+	Remove(wi *workItem)
+	// Fix reorders the work item after its priority has changed
+	Fix(wi *workItem)
+	// Items returns the work items waiting, in no particular order
+	Items() []*workItem
+}
+
+// priorityScheduler performs the work of highest priority first
+type priorityScheduler struct {
+	heap *workHeap
+}
+
+func newPriorityScheduler(h *workHeap) *priorityScheduler {
+	heap.Init(h)
+	return &priorityScheduler{heap: h}
+}
+
+func (s *priorityScheduler) Len() int {
+	return s.heap.Len()
+}
+
+func (s *priorityScheduler) Push(wi *workItem) {
+	heap.Push(s.heap, wi)
+}
+
+func (s *priorityScheduler) Pop(eligible func(wi *workItem) bool) *workItem {
+	s.heap.AdjustPriorities()
+	skipped := []*workItem{}
+	defer func() {
+		for _, wi := range skipped {
+			heap.Push(s.heap, wi)
+		}
+	}()
+
+	for s.heap.Len() > 0 {
+		wi := heap.Pop(s.heap).(*workItem)
+		if eligible(wi) {
+			return wi
+		}
+		skipped = append(skipped, wi)
+	}
+	return nil
+}
+
+func (s *priorityScheduler) Remove(wi *workItem) {
+	s.heap.Remove(wi.position)
+}
+
+func (s *priorityScheduler) Fix(wi *workItem) {
+	if wi.position >= 0 {
+		heap.Fix(s.heap, wi.position)
+	}
+}
+
+func (s *priorityScheduler) Items() []*workItem {
+	return append([]*workItem{}, s.heap.items...)
+}
+
+// fairScheduler shares workers between tenants in proportion to their weights, performing each tenant's work with the tenant's own
+// scheduler.  Tenants are served in order of their pass, which advances by the inverse of the tenant's weight each time the tenant is
+// served.  A tenant with no waiting work has its pass caught up to the pass of the last tenant served when work is next pushed, so it does
+// not accumulate credit while idle.  A tenant with no waiting work is forgotten, so tenants that come and go do not accumulate, at the cost of
+// the tenant losing at most one turn of the pass it was ahead by.
+type fairScheduler struct {
+	weights      map[string]int
+	newScheduler func(length int) scheduler
+	tenants      map[string]*tenantQueue
+	virtualTime  float64
+}
+
+type tenantQueue struct {
+	key       string
+	scheduler scheduler
+	pass      float64
+}
+
+func newFairScheduler(weights map[string]int, newScheduler func(length int) scheduler) *fairScheduler {
+	return &fairScheduler{
+		weights:      weights,
+		newScheduler: newScheduler,
+		tenants:      map[string]*tenantQueue{},
+	}
+}
+
+func (s *fairScheduler) Len() int {
+	length := 0
+	for _, t := range s.tenants {
+		length += t.scheduler.Len()
+	}
+	return length
+}
+
+func (s *fairScheduler) Push(wi *workItem) {
+	t, ok := s.tenants[wi.tenant]
+	if !ok {
+		t = &tenantQueue{key: wi.tenant, scheduler: s.newScheduler(0)}
+		s.tenants[wi.tenant] = t
+	}
+	if t.scheduler.Len() == 0 {
+		t.pass = max(t.pass, s.virtualTime)
+	}
+	t.scheduler.Push(wi)
+}
+
+func (s *fairScheduler) Pop(eligible func(wi *workItem) bool) *workItem {
+	waiting := make([]*tenantQueue, 0, len(s.tenants))
+	for _, t := range s.tenants {
+		if t.scheduler.Len() > 0 {
+			waiting = append(waiting, t)
+		} else {
+			delete(s.tenants, t.key)
+		}
+	}
+	sort.Slice(waiting, func(i, j int) bool {
+		if waiting[i].pass == waiting[j].pass {
+			return waiting[i].key < waiting[j].key
+		}
+		return waiting[i].pass < waiting[j].pass
+	})
+
+	for _, t := range waiting {
+		if wi := t.scheduler.Pop(eligible); wi != nil {
+			s.virtualTime = t.pass
+			t.pass += 1 / float64(s.weight(t.key))
+			return wi
+		}
+	}
+	return nil
+}
+
+func (s *fairScheduler) Remove(wi *workItem) {
+	if t, ok := s.tenants[wi.tenant]; ok {
+		t.scheduler.Remove(wi)
+		if t.scheduler.Len() == 0 {
+			delete(s.tenants, t.key)
+		}
+	}
+}
+
+func (s *fairScheduler) Fix(wi *workItem) {
+	if t, ok := s.tenants[wi.tenant]; ok {
+		t.scheduler.Fix(wi)
+	}
+}
+
+func (s *fairScheduler) Items() []*workItem {
+	items := []*workItem{}
+	for _, t := range s.tenants {
+		items = append(items, t.scheduler.Items()...)
+	}
+	return items
+}
+
+// weight returns the weight of the tenant, which is 1 unless configured otherwise
+func (s *fairScheduler) weight(tenant string) int {
+	if weight, ok := s.weights[tenant]; ok && weight > 0 {
+		return weight
+	}
+	return 1
+}
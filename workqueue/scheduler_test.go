@@ -0,0 +1,163 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func schedulerItem(name, tenant string, priority int, queuedAt time.Time) *workItem {
+	wi := newWorkItem(uuid.New(), nil, WithName(name), WithTenant(tenant), WithPriority(priority))
+	wi.queuedAt = queuedAt
+	return wi
+}
+
+func popNames(s scheduler) []string {
+	names := []string{}
+	for wi := s.Pop(func(*workItem) bool { return true }); wi != nil; wi = s.Pop(func(*workItem) bool { return true }) {
+		names = append(names, wi.name)
+	}
+	return names
+}
+
+func TestPriorityScheduler_Pop_SkipsIneligibleWork(t *testing.T) {
+	// setup
+	s := newPriorityScheduler(newWorkHeap(10))
+	s.Push(schedulerItem("work1", "", 1, time.Now()))
+	s.Push(schedulerItem("work2", "", 2, time.Now()))
+	s.Push(schedulerItem("work3", "", 3, time.Now()))
+
+	// test
+	wi := s.Pop(func(wi *workItem) bool { return wi.name != "work1" })
+
+	// assert
+	assert.Equal(t, "work2", wi.name)
+	assert.Equal(t, []string{"work1", "work3"}, popNames(s))
+}
+
+func TestAgingHeap_BoostsWaitingWork(t *testing.T) {
+	// setup
+	s := newPriorityScheduler(newAgingHeap(10, time.Second))
+	now := time.Now()
+	s.Push(schedulerItem("old low priority", "", 5, now.Add(-time.Second*10)))
+	s.Push(schedulerItem("new high priority", "", 1, now))
+	s.Push(schedulerItem("recent low priority", "", 5, now.Add(-time.Second)))
+
+	// test
+	names := popNames(s)
+
+	// assert
+	assert.Equal(t, []string{"old low priority", "new high priority", "recent low priority"}, names)
+}
+
+func TestFairScheduler_SharesByWeight(t *testing.T) {
+	// setup
+	s := newFairScheduler(map[string]int{"a": 2}, func(length int) scheduler {
+		return newPriorityScheduler(newWorkHeap(length))
+	})
+	for i := 0; i < 6; i++ {
+		s.Push(schedulerItem("a", "a", 1, time.Now()))
+		s.Push(schedulerItem("b", "b", 1, time.Now()))
+	}
+
+	// test
+	names := popNames(s)
+
+	// assert
+	assert.Equal(t, "a b a a b a a b a b b b", strings.Join(names, " "))
+}
+
+func TestFairScheduler_IdleTenantDoesNotAccumulateCredit(t *testing.T) {
+	// setup
+	s := newFairScheduler(nil, func(length int) scheduler {
+		return newPriorityScheduler(newWorkHeap(length))
+	})
+	s.Push(schedulerItem("a", "a", 1, time.Now()))
+	s.Push(schedulerItem("b", "b", 1, time.Now()))
+	_ = popNames(s)
+	// b is idle while a is served
+	for i := 0; i < 4; i++ {
+		s.Push(schedulerItem("a", "a", 1, time.Now()))
+	}
+	_ = popNames(s)
+
+	// test
+	for i := 0; i < 2; i++ {
+		s.Push(schedulerItem("a", "a", 1, time.Now()))
+		s.Push(schedulerItem("b", "b", 1, time.Now()))
+	}
+	names := popNames(s)
+
+	// assert
+	// b alternates with a rather than being served until it catches up, and a's lead is forgotten once it has no waiting work
+	assert.Equal(t, "a b a b", strings.Join(names, " "))
+}
+
+func TestFairScheduler_ForgetsTenantsWithoutWork(t *testing.T) {
+	// setup
+	s := newFairScheduler(nil, func(length int) scheduler {
+		return newPriorityScheduler(newWorkHeap(length))
+	})
+	removed := schedulerItem("removed", "removed", 1, time.Now())
+	s.Push(removed)
+	for i := 0; i < 100; i++ {
+		s.Push(schedulerItem("customer", fmt.Sprintf("customer %v", i), 1, time.Now()))
+	}
+
+	// test
+	s.Remove(removed)
+	_ = popNames(s)
+
+	// assert
+	assert.Equal(t, 0, s.Len())
+	assert.Empty(t, s.tenants)
+}
+
+func TestQueue_WithFairScheduling_ServesAllTenants(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithQueueLength(20), WithFairScheduling(nil))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	mux := &sync.Mutex{}
+	order := []string{}
+	record := func(name string) Work {
+		return func() error {
+			mux.Lock()
+			defer mux.Unlock()
+			order = append(order, name)
+			return nil
+		}
+	}
+	for i := 0; i < 4; i++ {
+		_, _ = q.Enqueue(record("flood"), WithTenant("flood"), WithPriority(1))
+	}
+	_, _ = q.Enqueue(record("other"), WithTenant("other"), WithPriority(5))
+
+	// test
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Len(t, order, 5)
+	assert.Contains(t, order[:2], "other")
+}
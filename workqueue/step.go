@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+// step is work dispatched for a call to Step, with a channel closed once the work has been performed
+type step struct {
+	wi   *workItem
+	done chan struct{}
+}
+
+// Step dispatches the next eligible work on a queue created WithManualDispatch, returning once the work has been performed.  False is
+// returned if no work is eligible, the queue is paused or stopped, or the queue does not dispatch manually.  Work that failed and will be
+// retried is returned once its attempt has been performed.
+func (w *Queue) Step() (*QueuedWork, bool) {
+	w.queueMux.Lock()
+	if !w.manual || w.queueContext.Err() != nil {
+		w.queueMux.Unlock()
+		return nil, false
+	}
+	ch := make(chan step, 1)
+	w.steps = append(w.steps, ch)
+	w.queueMux.Unlock()
+	w.signalWake()
+
+	s, ok := <-ch
+	if !ok {
+		return nil, false
+	}
+	<-s.done
+	return s.wi.QueuedWork, true
+}
+
+// stepped hands the work dispatched to the oldest pending call to Step.  queueMux must be held.
+func (w *Queue) stepped(wi *workItem) {
+	s := step{wi: wi, done: make(chan struct{})}
+	wi.stepDone = s.done
+	w.steps[0] <- s
+	w.steps = w.steps[1:]
+}
+
+// closeSteps returns pending calls to Step without performing work.  queueMux must be held.
+func (w *Queue) closeSteps() {
+	for _, ch := range w.steps {
+		close(ch)
+	}
+	w.steps = nil
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownHandler is returned when work is enqueued for a handler that has not been registered
+var ErrUnknownHandler = errors.New("unknown handler")
+
+// StoredWork is the serializable form of work enqueued for a registered handler
+type StoredWork struct {
+	Id         uuid.UUID       `json:"id"`
+	Handler    string          `json:"handler"`
+	Name       string          `json:"name,omitempty"`
+	Priority   int             `json:"priority"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	EnqueuedAt time.Time       `json:"enqueuedAt"`
+	RunAt      time.Time       `json:"runAt"`
+}
+
+// Storage persists work enqueued for registered handlers so it can be replayed if the process restarts before the work has finished.
+// Implementations must be safe for concurrent use.
+type Storage interface {
+	// Save persists work that has been enqueued
+	Save(work *StoredWork) error
+	// Remove removes work that has finished
+	Remove(id uuid.UUID) error
+	// Load returns the work that has been saved and not removed, in the order it was saved
+	Load() ([]*StoredWork, error)
+}
+
+// Handler performs work enqueued by name with a serialized payload
+type Handler func(ctx context.Context, payload []byte) error
+
+// HandlerRegistry maps names to the handlers performing work enqueued with EnqueueHandler
+type HandlerRegistry struct {
+	mux      *sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewHandlerRegistry returns a reference to an initialized HandlerRegistry
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{
+		mux:      &sync.RWMutex{},
+		handlers: map[string]Handler{},
+	}
+}
+
+// Register registers the handler with the name, replacing any handler previously registered with the name
+func (r *HandlerRegistry) Register(name string, handler Handler) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.handlers[name] = handler
+}
+
+// Handler returns the handler registered with the name
+func (r *HandlerRegistry) Handler(name string) (Handler, bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	handler, ok := r.handlers[name]
+	return handler, ok
+}
+
+// RegisterHandler registers a handler receiving its payload decoded from JSON with the name
+func RegisterHandler[T any](registry *HandlerRegistry, name string, handler func(ctx context.Context, payload T) error) {
+	registry.Register(name, func(ctx context.Context, data []byte) error {
+		var payload T
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return fmt.Errorf("decoding payload for handler %v: %w", name, err)
+		}
+		return handler(ctx, payload)
+	})
+}
+
+// EnqueueHandler queues work performed by the handler registered with the name, passing it the payload encoded as JSON.  If the queue has
+// storage, the work is saved before it is queued and removed once it has finished, so work that has not finished when the process exits is
+// replayed when a queue is next created with the storage.  Work skipped because the queue stopped is kept in storage.  Only the work's name,
+// priority and the time delayed work is due are saved with it, other options are not replayed.
+func (w *Queue) EnqueueHandler(ctx context.Context, handler string, payload any, options ...workOption) (uuid.UUID, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("encoding payload for handler %v: %w", handler, err)
+	}
+
+	wi, err := w.handlerWork(&StoredWork{Id: uuid.New(), Handler: handler, Payload: data}, options...)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if w.storage != nil {
+		err = w.storage.Save(&StoredWork{
+			Id:         wi.id,
+			Handler:    handler,
+			Name:       wi.name,
+			Priority:   wi.priority,
+			Payload:    data,
+			EnqueuedAt: w.clock.Now(),
+			RunAt:      wi.runAt,
+		})
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("saving work for handler %v: %w", handler, err)
+		}
+		wi.storage = w.storage
+	}
+
+	id, err := w.enqueue(ctx, wi)
+	// work dropped or coalesced into existing work is not performed
+	if err != nil || id != wi.id {
+		w.removeStored(wi)
+	}
+	return id, err
+}
+
+// handlerWork returns a work item performing the stored work with its registered handler
+func (w *Queue) handlerWork(stored *StoredWork, options ...workOption) (*workItem, error) {
+	var handler Handler
+	ok := false
+	if w.handlers != nil {
+		handler, ok = w.handlers.Handler(stored.Handler)
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownHandler, stored.Handler)
+	}
+
+	payload := stored.Payload
+	return w.newWorkItem(stored.Id, func(ctx context.Context) error {
+		return handler(ctx, payload)
+	}, options...), nil
+}
+
+// replay queues the work left in storage when the queue was created.  Replayed work is queued regardless of the queue's length.  Work for
+// handlers that are not registered is left in storage.
+func (w *Queue) replay() {
+	if w.storage == nil {
+		return
+	}
+
+	ctx := context.Background()
+	stored, err := w.storage.Load()
+	if err != nil {
+		w.log(ctx, LogStorageFailed, "loading stored work failed", slog.Any("error", err))
+		return
+	}
+
+	for _, sw := range stored {
+		wi, err := w.handlerWork(sw, WithName(sw.Name), WithPriority(sw.Priority), WithRunAt(sw.RunAt))
+		if err != nil {
+			w.log(ctx, LogStorageFailed, "replaying stored work failed", slog.String("id", sw.Id.String()), slog.Any("error", err))
+			continue
+		}
+		wi.storage = w.storage
+		wi.enqueuedAt = sw.EnqueuedAt
+
+		w.queueMux.Lock()
+		queued := w.queueWork(ctx, wi)
+		w.queueMux.Unlock()
+
+		w.onEnqueued(wi)
+		w.logWork(ctx, LogEnqueued, "work enqueued", wi, slog.Int("queued", queued), slog.Bool("replayed", true))
+	}
+}
+
+// removeStored removes stored work from the storage it was saved in, which is the storage of the queue it was enqueued on even if it spilled
+// to another queue
+func (w *Queue) removeStored(wi *workItem) {
+	if wi.storage == nil {
+		return
+	}
+	if err := wi.storage.Remove(wi.id); err != nil {
+		w.logWork(context.Background(), LogStorageFailed, "removing stored work failed", wi, slog.Any("error", err))
+	}
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPayload struct {
+	Value int `json:"value"`
+}
+
+// recordingHandlers returns a registry with a handler recording the values of the payloads it receives
+func recordingHandlers() (*HandlerRegistry, func() []int) {
+	mux := &sync.Mutex{}
+	values := []int{}
+	registry := NewHandlerRegistry()
+	RegisterHandler(registry, "record", func(ctx context.Context, payload testPayload) error {
+		mux.Lock()
+		defer mux.Unlock()
+		values = append(values, payload.Value)
+		return nil
+	})
+	return registry, func() []int {
+		mux.Lock()
+		defer mux.Unlock()
+		return append([]int{}, values...)
+	}
+}
+
+func TestQueue_EnqueueHandler_PerformsWorkAndRemovesStoredWork(t *testing.T) {
+	// setup
+	storage, err := NewFileStorage(filepath.Join(t.TempDir(), "queue.wal"))
+	assert.NoError(t, err)
+	defer storage.Close()
+	registry, values := recordingHandlers()
+	q := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+
+	// test
+	_, err = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 1})
+	assert.NoError(t, err)
+	_, err = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 2})
+	assert.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []int{1, 2}, values())
+	stored, _ := storage.Load()
+	assert.Empty(t, stored)
+}
+
+func TestQueue_EnqueueHandler_UnknownHandler_ReturnsError(t *testing.T) {
+	// setup
+	registry, _ := recordingHandlers()
+	q := NewQueue(WithHandlers(registry))
+	defer q.Stop()
+
+	// test
+	_, err := q.EnqueueHandler(context.Background(), "unknown", testPayload{})
+
+	// assert
+	assert.ErrorIs(t, err, ErrUnknownHandler)
+}
+
+func TestQueue_WithStorage_ReplaysUnfinishedWork(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path)
+	assert.NoError(t, err)
+	registry := NewHandlerRegistry()
+	started := make(chan struct{})
+	RegisterHandler(registry, "record", func(ctx context.Context, payload testPayload) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+	_, _ = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 1})
+	<-started
+	_, _ = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 2}, WithName("queued"), WithPriority(3))
+	// simulate a crash while the first work is in process and the second is queued
+	assert.NoError(t, storage.Close())
+
+	// test
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	registry, values := recordingHandlers()
+	q2 := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q2.Shutdown(ctx))
+	q.Break()
+
+	// assert
+	assert.Equal(t, []int{1, 2}, values())
+	history := q2.History()
+	if assert.Len(t, history, 2) {
+		assert.Equal(t, "queued", history[1].Name())
+		assert.Equal(t, 3, history[1].Priority())
+	}
+}
+
+func TestQueue_Break_KeepsStoredWork(t *testing.T) {
+	// setup
+	storage, err := NewFileStorage(filepath.Join(t.TempDir(), "queue.wal"))
+	assert.NoError(t, err)
+	defer storage.Close()
+	registry, _ := recordingHandlers()
+	q := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	id, _ := q.EnqueueHandler(context.Background(), "record", testPayload{Value: 1})
+
+	// test
+	q.Break()
+	close(release)
+
+	// assert
+	stored, _ := storage.Load()
+	if assert.Len(t, stored, 1) {
+		assert.Equal(t, id, stored[0].Id)
+	}
+	assert.NotEqual(t, uuid.Nil, id)
+}
+
+func TestQueue_Stop_KeepsCancelledStoredWorkForReplay(t *testing.T) {
+	// setup
+	path := filepath.Join(t.TempDir(), "queue.wal")
+	storage, err := NewFileStorage(path)
+	assert.NoError(t, err)
+	registry := NewHandlerRegistry()
+	started := make(chan struct{}, 2)
+	RegisterHandler(registry, "record", func(ctx context.Context, payload testPayload) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	q := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+	_, _ = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 1})
+	<-started
+	_, _ = q.EnqueueHandler(context.Background(), "record", testPayload{Value: 2})
+
+	// test
+	q.Stop()
+	<-started
+	assert.Eventually(t, func() bool {
+		return q.Stats().Cancelled == 2
+	}, time.Second, time.Millisecond*5)
+	assert.NoError(t, storage.Close())
+	storage, err = NewFileStorage(path)
+	assert.NoError(t, err)
+	defer storage.Close()
+	registry, values := recordingHandlers()
+	q2 := NewQueue(WithWorkers(1), WithStorage(storage), WithHandlers(registry))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, q2.Shutdown(ctx))
+
+	// assert
+	assert.Equal(t, []int{1, 2}, values())
+	stored, _ := storage.Load()
+	assert.Empty(t, stored)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"errors"
+)
+
+// ErrDuplicateWork is returned when work is enqueued with the unique key of work that has not started, and the queue drops duplicate work
+var ErrDuplicateWork = errors.New("duplicate work")
+
+// ErrWorkReplaced is the error of work cancelled because work with the same unique key replaced it
+var ErrWorkReplaced = errors.New("work replaced")
+
+// DuplicatePolicy determines what happens when work is enqueued with the unique key of work that has not started
+type DuplicatePolicy int
+
+// duplicate policies
+const (
+	// CoalesceDuplicates drops the new work, returning the id of the existing work
+	CoalesceDuplicates DuplicatePolicy = iota
+	// DropDuplicates drops the new work, returning ErrDuplicateWork
+	DropDuplicates
+	// ReplaceDuplicates cancels the existing work with ErrWorkReplaced, queuing the new work in its place
+	ReplaceDuplicates
+)
+
+// duplicate returns the work that has not started with the work item's unique key.  queueMux must be held.
+func (w *Queue) duplicate(wi *workItem) (*workItem, bool) {
+	if wi.uniqueKey == "" {
+		return nil, false
+	}
+	existing, ok := w.uniqueKeys[wi.uniqueKey]
+	return existing, ok
+}
+
+// coalesce arranges for the work item's finish callback to be called when the existing work finishes, recording the existing work on the
+// work item.  The existing work's unique key is released under queueMux before it finishes, so the callback is always seen.  queueMux must
+// be held.
+func coalesce(existing, wi *workItem) {
+	wi.coalescedInto = existing.QueuedWork
+	if wi.onFinish == nil {
+		return
+	}
+	if existing.onFinish == nil {
+		existing.onFinish = wi.onFinish
+		return
+	}
+	first, second := existing.onFinish, wi.onFinish
+	existing.onFinish = func(err error) {
+		first(err)
+		second(err)
+	}
+}
+
+// cancelPending cancels work that has not started, removing it from the queue, returning false if the work has started or finished.
+// queueMux must be held.
+func (w *Queue) cancelPending(wi *workItem) bool {
+	switch {
+	case wi.state.CompareAndSwap(int32(IN_QUEUE), int32(CANCELLED)):
+		w.workQueue.Remove(wi)
+	case wi.state.CompareAndSwap(int32(SCHEDULED), int32(CANCELLED)):
+		w.scheduled.Remove(wi.position)
+	case wi.state.CompareAndSwap(int32(WAITING), int32(CANCELLED)):
+	default:
+		return false
+	}
+	return true
+}
+
+// releaseUniqueKey releases the work item's unique key once the work has started or finished, so work with the same key can be queued.
+// queueMux must be held.
+func (w *Queue) releaseUniqueKey(wi *workItem) {
+	if wi.uniqueKey != "" && w.uniqueKeys[wi.uniqueKey] == wi {
+		delete(w.uniqueKeys, wi.uniqueKey)
+	}
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueue
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockedQueue returns a queue with a single worker that is busy until the returned channel is closed
+func blockedQueue(options ...WorkQueueOption) (*Queue, chan struct{}) {
+	q := NewQueue(append([]WorkQueueOption{WithWorkers(1)}, options...)...)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	return q, release
+}
+
+func TestQueue_WithUniqueKey_CoalescesDuplicates(t *testing.T) {
+	// setup
+	q, release := blockedQueue()
+	defer q.Stop()
+	performed := atomic.Int32{}
+	work := func(ctx context.Context) (int32, error) {
+		return performed.Add(1), nil
+	}
+	first := EnqueueFunc(context.Background(), q, work, WithUniqueKey("reindex 42"))
+
+	// test
+	second := EnqueueFunc(context.Background(), q, work, WithUniqueKey("reindex 42"))
+	close(release)
+
+	// assert
+	assert.Equal(t, first.Id(), second.Id())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := second.Wait(ctx)
+	assert.NoError(t, err)
+	value, _ := first.Wait(ctx)
+	assert.Equal(t, int32(1), value)
+	assert.Equal(t, int32(1), performed.Load())
+}
+
+func TestQueue_WithUniqueKey_DropsDuplicates(t *testing.T) {
+	// setup
+	q, release := blockedQueue(WithDuplicatePolicy(DropDuplicates))
+	defer q.Stop()
+	defer close(release)
+	_, err := q.Enqueue(func() error { return nil }, WithUniqueKey("reindex 42"))
+	assert.NoError(t, err)
+
+	// test
+	_, errDuplicate := q.Enqueue(func() error { return nil }, WithUniqueKey("reindex 42"))
+	_, errOther := q.Enqueue(func() error { return nil }, WithUniqueKey("reindex 43"))
+
+	// assert
+	assert.ErrorIs(t, errDuplicate, ErrDuplicateWork)
+	assert.NoError(t, errOther)
+}
+
+func TestQueue_WithUniqueKey_ReplacesDuplicates(t *testing.T) {
+	// setup
+	q, release := blockedQueue(WithDuplicatePolicy(ReplaceDuplicates))
+	defer q.Stop()
+	first := EnqueueFunc(context.Background(), q, func(ctx context.Context) (string, error) {
+		return "first", nil
+	}, WithUniqueKey("reindex 42"))
+
+	// test
+	second := EnqueueFunc(context.Background(), q, func(ctx context.Context) (string, error) {
+		return "second", nil
+	}, WithUniqueKey("reindex 42"))
+	close(release)
+
+	// assert
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := first.Wait(ctx)
+	assert.ErrorIs(t, err, ErrWorkReplaced)
+	value, err := second.Wait(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", value)
+	work, _ := q.FindWork(first.Id())
+	assert.Equal(t, CANCELLED.String(), work.State())
+}
+
+func TestQueue_WithUniqueKey_StartedWork_QueuesNewWork(t *testing.T) {
+	// setup
+	q := NewQueue(WithWorkers(1), WithDuplicatePolicy(DropDuplicates))
+	defer q.Stop()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	_, _ = q.Enqueue(func() error {
+		close(started)
+		<-release
+		return nil
+	}, WithUniqueKey("reindex 42"))
+	<-started
+
+	// test
+	_, err := q.Enqueue(func() error { return nil }, WithUniqueKey("reindex 42"))
+	close(release)
+
+	// assert
+	assert.NoError(t, err)
+}
@@ -9,18 +9,46 @@ package workqueue
 import (
 	"container/heap"
 	"sync"
+	"time"
 )
 
 // workHeap implements heap interface for prioritizing work to be worked on when queued
 type workHeap struct {
 	items []*workItem
 	mux   *sync.RWMutex
+	less  func(a, b *workItem) bool
 }
 
 func newWorkHeap(length int) *workHeap {
 	return &workHeap{
 		items: make([]*workItem, 0, length),
 		mux:   &sync.RWMutex{},
+		less: func(a, b *workItem) bool {
+			return a.priority < b.priority
+		},
+	}
+}
+
+// newAgingHeap returns a workHeap ordering work by priority, where work waiting on the heap is boosted one priority for each interval it has
+// waited.  As all work ages at the same rate, work is ordered by the time it was queued offset by the interval for each priority.
+func newAgingHeap(length int, interval time.Duration) *workHeap {
+	return &workHeap{
+		items: make([]*workItem, 0, length),
+		mux:   &sync.RWMutex{},
+		less: func(a, b *workItem) bool {
+			return a.queuedAt.Add(time.Duration(a.priority) * interval).Before(b.queuedAt.Add(time.Duration(b.priority) * interval))
+		},
+	}
+}
+
+// newScheduleHeap returns a workHeap ordering work by the time it is due
+func newScheduleHeap() *workHeap {
+	return &workHeap{
+		items: []*workItem{},
+		mux:   &sync.RWMutex{},
+		less: func(a, b *workItem) bool {
+			return a.runAt.Before(b.runAt)
+		},
 	}
 }
 
@@ -30,7 +58,7 @@ func (wh workHeap) AdjustPriorities() {
 		if wi.adjustPriority != nil {
 			newPriority := wi.adjustPriority()
 			if newPriority != wi.priority {
-				wi.priority = newPriority
+				wi.setPriority(newPriority)
 				heap.Fix(&wh, wi.position)
 			}
 		}
@@ -48,7 +76,7 @@ func (wh *workHeap) Len() int {
 func (wh *workHeap) Less(i, j int) bool {
 	wh.mux.RLock()
 	defer wh.mux.RUnlock()
-	return wh.items[i].priority < wh.items[j].priority
+	return wh.less(wh.items[i], wh.items[j])
 }
 
 // Swap swaps the work items at index i and j
@@ -66,7 +94,6 @@ func (wh *workHeap) Push(x any) {
 	defer wh.mux.Unlock()
 	n := len(wh.items)
 	item := x.(*workItem)
-	item.state.Store(int32(IN_QUEUE))
 	item.position = n
 	wh.items = append(wh.items, item)
 }
@@ -84,6 +111,16 @@ func (wh *workHeap) Pop() any {
 	return item
 }
 
+// Peek returns the work item at the top of the heap without removing it, or nil if the heap is empty
+func (wh *workHeap) Peek() *workItem {
+	wh.mux.RLock()
+	defer wh.mux.RUnlock()
+	if len(wh.items) == 0 {
+		return nil
+	}
+	return wh.items[0]
+}
+
 // Remove removes the work item with the id
 func (wh *workHeap) Remove(position int) {
 	if position >= 0 {
@@ -8,6 +8,7 @@ package workqueue
 
 import (
 	"container/heap"
+	"context"
 	"sync/atomic"
 	"testing"
 
@@ -20,7 +21,7 @@ func TestWorkHeap_Push(t *testing.T) {
 	heap.Init(wh)
 
 	work := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
@@ -43,7 +44,7 @@ func TestWorkHeap_Pop(t *testing.T) {
 	heap.Init(wh)
 
 	work := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
@@ -69,7 +70,7 @@ func TestWorkHeap_Priority_Pop(t *testing.T) {
 	heap.Init(wh)
 
 	work1 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
@@ -81,7 +82,7 @@ func TestWorkHeap_Priority_Pop(t *testing.T) {
 	}
 	heap.Push(wh, work1)
 	work2 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
@@ -93,7 +94,7 @@ func TestWorkHeap_Priority_Pop(t *testing.T) {
 	}
 	heap.Push(wh, work2)
 	work3 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
@@ -123,7 +124,7 @@ func TestWorkHeap_AdjustPriorities_ChangesPriorities(t *testing.T) {
 	heap.Init(wh)
 
 	work1 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		adjustPriority: func() int {
@@ -137,7 +138,7 @@ func TestWorkHeap_AdjustPriorities_ChangesPriorities(t *testing.T) {
 	}
 	heap.Push(wh, work1)
 	work2 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		adjustPriority: func() int {
@@ -151,7 +152,7 @@ func TestWorkHeap_AdjustPriorities_ChangesPriorities(t *testing.T) {
 	}
 	heap.Push(wh, work2)
 	work3 := &workItem{
-		workToDo: func() error {
+		workToDo: func(context.Context) error {
 			return nil
 		},
 		QueuedWork: &QueuedWork{
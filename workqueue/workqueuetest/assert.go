@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueuetest
+
+import (
+	"slices"
+
+	"github.com/rbell/toolchest/workqueue"
+)
+
+// TestingT is the subset of *testing.T used to report failed assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertDispatchOrder runs all eligible work on the queue, asserting the work was dispatched in the order of the names
+func AssertDispatchOrder(t TestingT, q *Queue, names ...string) bool {
+	t.Helper()
+	dispatched := Names(q.RunAll())
+	if !slices.Equal(dispatched, names) {
+		t.Errorf("work dispatched in order %q, expected %q", dispatched, names)
+		return false
+	}
+	return true
+}
+
+// AssertPriorityOrder asserts the work was dispatched in order of priority, with no work dispatched ahead of work of a higher priority
+// (lower number)
+func AssertPriorityOrder(t TestingT, work []*workqueue.QueuedWork) bool {
+	t.Helper()
+	for i := 1; i < len(work); i++ {
+		if work[i].Priority() < work[i-1].Priority() {
+			t.Errorf("work %v of priority %v dispatched after work %v of priority %v", label(work[i]), work[i].Priority(), label(work[i-1]),
+				work[i-1].Priority())
+			return false
+		}
+	}
+	return true
+}
+
+// Names returns the names of the work
+func Names(work []*workqueue.QueuedWork) []string {
+	names := make([]string, len(work))
+	for i, w := range work {
+		names[i] = w.Name()
+	}
+	return names
+}
+
+// label returns the name of the work, or its id if it is not named
+func label(work *workqueue.QueuedWork) string {
+	if work.Name() != "" {
+		return work.Name()
+	}
+	return work.Id()
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueuetest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is a workqueue.Clock whose time only moves when advanced, calling the functions and expiring the deadlines due as it passes them
+type Clock struct {
+	mux    *sync.Mutex
+	now    time.Time
+	seq    uint64
+	timers map[uint64]*timer
+}
+
+type timer struct {
+	due time.Time
+	seq uint64
+	f   func()
+}
+
+// NewClock returns a reference to a Clock starting at the time
+func NewClock(now time.Time) *Clock {
+	return &Clock{
+		mux:    &sync.Mutex{},
+		now:    now,
+		timers: map[uint64]*timer{},
+	}
+}
+
+// Now returns the clock's current time
+func (c *Clock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+// AfterFunc calls f once the clock has been advanced by the duration.  If the duration has already passed, f is called in its own
+// goroutine.
+func (c *Clock) AfterFunc(d time.Duration, f func()) func() bool {
+	if d <= 0 {
+		go f()
+		return func() bool { return false }
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.seq++
+	seq := c.seq
+	c.timers[seq] = &timer{due: c.now.Add(d), seq: seq, f: f}
+	return func() bool {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		_, ok := c.timers[seq]
+		delete(c.timers, seq)
+		return ok
+	}
+}
+
+// WithDeadline returns a copy of the parent context that is done with context.DeadlineExceeded once the clock passes the deadline
+func (c *Clock) WithDeadline(parent context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	ctx := &deadlineContext{
+		Context:  parent,
+		deadline: deadline,
+		mux:      &sync.Mutex{},
+		done:     make(chan struct{}),
+	}
+	stopTimer := c.AfterFunc(deadline.Sub(c.Now()), func() {
+		ctx.cancel(context.DeadlineExceeded)
+	})
+	stopParent := context.AfterFunc(parent, func() {
+		ctx.cancel(parent.Err())
+	})
+	return ctx, func() {
+		stopTimer()
+		stopParent()
+		ctx.cancel(context.Canceled)
+	}
+}
+
+// Advance moves the clock forward by the duration, calling the functions due in the order they are due.  Each function is called with the
+// clock at the time it was due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mux.Lock()
+	target := c.now.Add(d)
+	for {
+		var next *timer
+		for _, t := range c.timers {
+			if t.due.After(target) {
+				continue
+			}
+			if next == nil || t.due.Before(next.due) || (t.due.Equal(next.due) && t.seq < next.seq) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		delete(c.timers, next.seq)
+		if next.due.After(c.now) {
+			c.now = next.due
+		}
+		c.mux.Unlock()
+		next.f()
+		c.mux.Lock()
+	}
+	c.now = target
+	c.mux.Unlock()
+}
+
+// Set moves the clock forward to the time, calling the functions due in the order they are due
+func (c *Clock) Set(now time.Time) {
+	c.Advance(now.Sub(c.Now()))
+}
+
+// deadlineContext is a context done once its clock passes its deadline.  It has its own done channel so contexts derived from it take
+// their error from it rather than from its parent.
+type deadlineContext struct {
+	context.Context
+	deadline time.Time
+	mux      *sync.Mutex
+	done     chan struct{}
+	err      error
+}
+
+func (d *deadlineContext) Deadline() (time.Time, bool) {
+	return d.deadline, true
+}
+
+func (d *deadlineContext) Done() <-chan struct{} {
+	return d.done
+}
+
+func (d *deadlineContext) Err() error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return d.err
+}
+
+func (d *deadlineContext) cancel(err error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.err == nil {
+		d.err = err
+		close(d.done)
+	}
+}
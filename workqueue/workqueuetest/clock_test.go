@@ -0,0 +1,77 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueuetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock_Advance_CallsDueFunctionsInOrder(t *testing.T) {
+	// setup
+	clock := NewClock(Epoch)
+	called := []string{}
+	at := []time.Time{}
+	record := func(name string) func() {
+		return func() {
+			called = append(called, name)
+			at = append(at, clock.Now())
+		}
+	}
+	clock.AfterFunc(time.Second*2, record("second"))
+	clock.AfterFunc(time.Second, record("first"))
+	clock.AfterFunc(time.Second*3, record("later"))
+	stop := clock.AfterFunc(time.Second, record("stopped"))
+
+	// test
+	stopped := stop()
+	clock.Advance(time.Second * 2)
+
+	// assert
+	assert.True(t, stopped)
+	assert.Equal(t, []string{"first", "second"}, called)
+	assert.Equal(t, []time.Time{Epoch.Add(time.Second), Epoch.Add(time.Second * 2)}, at)
+	assert.Equal(t, Epoch.Add(time.Second*2), clock.Now())
+}
+
+func TestClock_WithDeadline_ExpiresWhenAdvanced(t *testing.T) {
+	// setup
+	clock := NewClock(Epoch)
+	ctx, cancel := clock.WithDeadline(context.Background(), Epoch.Add(time.Minute))
+	defer cancel()
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	// test
+	clock.Advance(time.Second)
+	before := ctx.Err()
+	clock.Set(Epoch.Add(time.Minute))
+
+	// assert
+	assert.NoError(t, before)
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+	<-child.Done()
+	assert.ErrorIs(t, child.Err(), context.DeadlineExceeded)
+}
+
+func TestClock_WithDeadline_ParentCancelled(t *testing.T) {
+	// setup
+	clock := NewClock(Epoch)
+	parent, parentCancel := context.WithCancel(context.Background())
+	ctx, cancel := clock.WithDeadline(parent, Epoch.Add(time.Minute))
+	defer cancel()
+
+	// test
+	parentCancel()
+
+	// assert
+	<-ctx.Done()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+// Package workqueuetest provides a deterministic work queue for tests: a queue that performs work one item at a time when stepped, a
+// clock the test advances to make delayed work due, retry work and time work out, and assertions on the order work is dispatched in.
+package workqueuetest
+
+import (
+	"time"
+
+	"github.com/rbell/toolchest/workqueue"
+)
+
+// defaultQueueLength is the length of test queues unless configured otherwise, large enough that tests enqueuing work before stepping the
+// queue do not block
+const defaultQueueLength = 1024
+
+// Epoch is the time the clocks of test queues start at
+var Epoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Queue is a workqueue.Queue that only performs work when stepped, telling the time with a clock the test controls.  Work is enqueued,
+// dequeued, reprioritized and inspected through the embedded queue.
+type Queue struct {
+	*workqueue.Queue
+	Clock *Clock
+}
+
+// NewQueue returns a reference to a Queue with a single worker and a clock starting at Epoch.  The options are applied after the test
+// queue's defaults, so may override the number of workers, the queue's length or the clock.
+func NewQueue(options ...workqueue.WorkQueueOption) *Queue {
+	clock := NewClock(Epoch)
+	defaults := []workqueue.WorkQueueOption{
+		workqueue.WithWorkers(1),
+		workqueue.WithQueueLength(defaultQueueLength),
+		workqueue.WithClock(clock),
+	}
+	options = append(defaults, options...)
+	options = append(options, workqueue.WithManualDispatch())
+	return &Queue{
+		Queue: workqueue.NewQueue(options...),
+		Clock: clock,
+	}
+}
+
+// RunAll steps the queue until no work is eligible, returning the work performed in the order it was dispatched
+func (q *Queue) RunAll() []*workqueue.QueuedWork {
+	performed := []*workqueue.QueuedWork{}
+	for {
+		work, ok := q.Step()
+		if !ok {
+			return performed
+		}
+		performed = append(performed, work)
+	}
+}
+
+// Advance moves the queue's clock forward by the duration, making delayed work and retries due
+func (q *Queue) Advance(d time.Duration) {
+	q.Clock.Advance(d)
+}
@@ -0,0 +1,218 @@
+/*
+ * Copyright (c) 2026 by Randy Bell.  All rights reserved.
+ *
+ * This Source Code Form is subject to the terms of the Apache Public License, version 2.0. If a copy of the APL was not distributed with this file, you can obtain one at https://www.apache.org/licenses/LICENSE-2.0.txt.
+ */
+
+package workqueuetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rbell/toolchest/workqueue"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingT records failed assertions
+type recordingT struct {
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func noWork() error {
+	return nil
+}
+
+func TestQueue_Step_PerformsOneItemAtATime(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	id, _ := q.Enqueue(noWork, workqueue.WithName("first"))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("second"))
+
+	// test
+	work, ok := q.Step()
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "first", work.Name())
+	assert.Equal(t, "Completed", work.State())
+	assert.Equal(t, id.String(), work.Id())
+	assert.Equal(t, 1, q.Stats().Queued)
+}
+
+func TestQueue_Step_TimesWorkWithClock(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(func() error {
+		q.Advance(time.Second)
+		return nil
+	})
+
+	// test
+	work, _ := q.Step()
+
+	// assert
+	assert.Equal(t, Epoch, work.EnqueuedAt())
+	assert.Equal(t, Epoch, work.StartedAt())
+	assert.Equal(t, Epoch.Add(time.Second), work.FinishedAt())
+	assert.Equal(t, time.Second, work.RunTime())
+}
+
+func TestQueue_Step_NoWork_ReturnsFalse(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+
+	// test
+	work, ok := q.Step()
+
+	// assert
+	assert.False(t, ok)
+	assert.Nil(t, work)
+}
+
+func TestAssertDispatchOrder_DispatchesByPriority(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(noWork, workqueue.WithName("low"), workqueue.WithPriority(3))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("high"), workqueue.WithPriority(1))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("medium"), workqueue.WithPriority(2))
+
+	// test
+	ok := AssertDispatchOrder(t, q, "high", "medium", "low")
+
+	// assert
+	assert.True(t, ok)
+}
+
+func TestAssertDispatchOrder_WrongOrder_Fails(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(noWork, workqueue.WithName("low"), workqueue.WithPriority(3))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("high"), workqueue.WithPriority(1))
+	rec := &recordingT{}
+
+	// test
+	ok := AssertDispatchOrder(rec, q, "low", "high")
+
+	// assert
+	assert.False(t, ok)
+	assert.Equal(t, []string{`work dispatched in order ["high" "low"], expected ["low" "high"]`}, rec.errors)
+}
+
+func TestAssertPriorityOrder(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(noWork, workqueue.WithName("low"), workqueue.WithPriority(3))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("high"), workqueue.WithPriority(1))
+	performed := q.RunAll()
+	rec := &recordingT{}
+
+	// test
+	ok := AssertPriorityOrder(t, performed)
+	reversed := AssertPriorityOrder(rec, []*workqueue.QueuedWork{performed[1], performed[0]})
+
+	// assert
+	assert.True(t, ok)
+	assert.False(t, reversed)
+	assert.Equal(t, []string{"work high of priority 1 dispatched after work low of priority 3"}, rec.errors)
+}
+
+func TestQueue_Advance_MakesDelayedWorkDue(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	_, _ = q.Enqueue(noWork, workqueue.WithName("later"), workqueue.WithDelay(time.Minute))
+	_, _ = q.Enqueue(noWork, workqueue.WithName("now"))
+
+	// test
+	early := Names(q.RunAll())
+	q.Advance(time.Minute)
+	due := Names(q.RunAll())
+
+	// assert
+	assert.Equal(t, []string{"now"}, early)
+	assert.Equal(t, []string{"later"}, due)
+}
+
+func TestQueue_Advance_RetriesAfterBackoff(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	attempts := 0
+	policy := workqueue.NewRetryPolicy(2, workqueue.WithBackoff(workqueue.ConstantBackoff(time.Second)))
+	_, _ = q.Enqueue(func() error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	}, workqueue.WithName("flaky"), workqueue.WithRetryPolicy(policy))
+
+	// test
+	first, _ := q.Step()
+	firstState := first.State()
+	_, beforeBackoff := q.Step()
+	q.Advance(time.Second)
+	second, ok := q.Step()
+
+	// assert
+	assert.Equal(t, "Retrying", firstState)
+	assert.False(t, beforeBackoff)
+	assert.True(t, ok)
+	assert.Equal(t, "Completed", second.State())
+	assert.Equal(t, 2, attempts)
+}
+
+func TestQueue_WithTimeout_TimesOutByClock(t *testing.T) {
+	// setup
+	q := NewQueue()
+	defer q.Stop()
+	id, _ := q.EnqueueContext(context.Background(), func(ctx context.Context) error {
+		deadline, _ := ctx.Deadline()
+		assert.Equal(t, Epoch.Add(time.Second), deadline)
+		q.Advance(time.Second)
+		<-ctx.Done()
+		return ctx.Err()
+	}, workqueue.WithTimeout(time.Second))
+
+	// test
+	work, _ := q.Step()
+
+	// assert
+	assert.Equal(t, id.String(), work.Id())
+	assert.ErrorIs(t, work.Err(), context.DeadlineExceeded)
+}
+
+func TestQueue_Advance_DrivesAutoscaling(t *testing.T) {
+	// setup
+	q := NewQueue(workqueue.WithAutoscaling(1, 4, workqueue.WithScaleInterval(time.Second)))
+	defer q.Stop()
+	for i := 0; i < 3; i++ {
+		_, _ = q.Enqueue(noWork)
+	}
+
+	// test
+	scaled := func() bool {
+		q.Advance(time.Second)
+		return q.Stats().Workers == 4
+	}
+
+	// assert
+	assert.Equal(t, 1, q.Stats().Workers)
+	assert.Eventually(t, scaled, time.Second, time.Millisecond*5)
+}